@@ -1,36 +1,90 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/gh"
 	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/sys"
 )
 
 var (
-	flagPreRelease  bool
-	flagToken       string
-	flagFilter      string
-	flagInstallName string
+	flagPreRelease bool
+	flagToken      string
+	flagYes        bool
+	flagSet        []string
 )
 
+// addFieldFlags maps add's string flags to the repo config keys they set.
+// Lists are comma-separated.
+var addFieldFlags = []struct {
+	flag, key, usage string
+}{
+	{"filter", "asset_filter", "Regex to prefer a specific asset (e.g., '.*musl.*')"},
+	{"exclude", "asset_exclude", "Regex of assets to never pick"},
+	{"name", "install_name", "Set a custom binary name for the executable"},
+	{"alias", "aliases", "Comma-separated extra names to refer to the repo by, e.g. rg"},
+	{"matcher-mode", "matcher_mode", "Asset matcher mode: strict or relaxed"},
+	{"asset-priority", "asset_priority", "Comma-separated keywords to prefer, e.g. x86_64,amd64"},
+	{"preferred-archives", "preferred_archives", "Comma-separated archive types to prefer, e.g. .tar.gz,.zip"},
+	{"fallback-arch", "fallback_arch", "Comma-separated architectures accepted in relaxed mode"},
+	{"fallback-os", "fallback_os", "Comma-separated operating systems accepted in relaxed mode"},
+	{"source", "source", "Release source: github (default), url or tags"},
+	{"url-template", "url_template", "Download URL template for url and tags sources"},
+	{"version-url", "version_check.url", "URL used to discover the latest version of a url source"},
+	{"version-strategy", "version_check.strategy", "Version discovery for url sources: json, redirect or regex (default regex)"},
+	{"version-json-path", "version_check.json_path", "Dot-separated path to the version in a JSON index"},
+	{"version-regex", "version_check.regex", "Regex matching versions in the page, redirect URL or JSON value"},
+}
+
 var addCmd = &cobra.Command{
 	Use:   "add <owner/repo>",
 	Short: "Add a GitHub repository to track",
-	Long: `Adds a new repository to the tracking list.
+	Long: `Adds a new repository to the tracking list and installs its latest release.
+
+Before anything is saved, the repository is checked on GitHub and the asset
+that would be installed is shown for confirmation. If the first install
+fails, the repository is removed from the config again.
 
 Examples:
   track add BurntSushi/ripgrep
-  track add jesseduffield/lazygit
+  track add jesseduffield/lazygit --filter '.*musl.*' --name lg --yes
+  track add sharkdp/fd --asset-priority x86_64,amd64 --preferred-archives .tar.gz
+  track add acme/tool --set build.always=true --set build.env=GOPROXY=off
+  track add hashicorp/terraform --source url \
+    --url-template 'https://releases.hashicorp.com/terraform/{{.Version}}/terraform_{{.Version}}_{{.OS}}_{{.Arch}}.zip' \
+    --version-strategy json --version-url https://checkpoint-api.hashicorp.com/v1/check/terraform --version-json-path current_version
+  track add kubernetes/kubectl --source url \
+    --url-template 'https://dl.k8s.io/release/{{.Tag}}/bin/{{.OS}}/{{.Arch}}/kubectl{{.Ext}}' \
+    --version-strategy regex --version-url https://dl.k8s.io/release/stable.txt
+  track add myorg/internal-cli --source tags \
+    --url-template 'https://artifacts.example.com/internal-cli/{{.Tag}}/internal-cli-{{.OS}}-{{.Arch}}.tar.gz'
 
 Flags:
-  --prerelease      Include pre-releases when checking for updates
-  --token           GitHub token for private repositories
-  --filter          Regex to prefer a specific asset (e.g., '.*musl.*')
-  --name            Set a custom binary name for the executable
+  --prerelease          Include pre-releases (default: global default_prerelease)
+  --token               GitHub token for private repositories
+  --filter, --exclude   Regexes selecting or rejecting assets
+  --name                Set a custom binary name for the executable
+  --alias               Comma-separated extra names for commands like 'track update <name>'
+  --matcher-mode        strict or relaxed
+  --asset-priority, --preferred-archives, --fallback-arch, --fallback-os
+                        Comma-separated matching preferences
+  --source              Release source: github (default), url or tags
+  --url-template        Download URL template for url and tags sources ({{.Tag}}, {{.Version}}, {{.OS}}, {{.Arch}}, {{.Ext}})
+  --version-*           Latest version discovery for url sources (strategy, url, json-path, regex)
+  --set key=value       Set any other repo key, e.g. build.command or smoke_test (repeatable)
+  --yes, -y             Install without asking for confirmation
+  --dry-run             Resolve the release, asset and links and print the plan without changing anything
 
-After adding, an initial update is run automatically.`,
+Notes:
+- Confirmation is only asked when stdin is a terminal; in scripts the
+  plan is printed and the install proceeds.
+- Run 'track config list --repo <owner/repo>' afterwards to see every setting.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		repoPath := args[0]
@@ -50,23 +104,134 @@ After adding, an initial update is run automatically.`,
 			fmt.Printf("Error: %v\n", err)
 			return
 		}
+		if _, exists := mgr.Cfg.Repos[repoPath]; exists {
+			fmt.Printf("Error: repository '%s' is already being tracked\n", repoPath)
+			return
+		}
 
-		if err := mgr.AddRepo(repoPath); err != nil {
-			fmt.Printf("Error adding repository: %v\n", err)
+		newRepo, err := repoFromFlags(cmd, repoPath, mgr.Cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			return
 		}
 
-		fmt.Println("\nRunning initial update...")
-		if err := mgr.UpdateRepo(repoPath, true); err != nil {
-			fmt.Printf("Error during initial update: %v\n", err)
+		if newRepo.Source != config.SourceURL {
+			owner, name, _ := strings.Cut(repoPath, "/")
+			token := os.Getenv("GITHUB_TOKEN")
+			if flagToken != "" {
+				token = flagToken
+			}
+			if _, err := gh.NewClient(context.Background(), token).GetRepo(context.Background(), owner, name); err != nil {
+				fmt.Printf("Error: repository '%s' not found on GitHub: %v\n", repoPath, err)
+				return
+			}
+		}
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if !dryRun && !flagYes {
+			enableAssetPicker(mgr)
+		}
+
+		release, err := mgr.LatestRelease(repoPath, newRepo)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		plan, err := mgr.PlanInstall(repoPath, newRepo, release)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			fmt.Println("Nothing was added. Adjust --filter, --exclude or --asset-priority, or see 'track search' and 'track releases'.")
+			return
+		}
+
+		if dryRun {
+			fmt.Println("Dry run: nothing will be downloaded, linked or saved.")
+			fmt.Printf("Would add '%s' to tracked repositories.\n", repoPath)
+			printPlan(plan)
+			return
+		}
+
+		printPlan(plan)
+		if !flagYes && sys.IsTerminal(os.Stdin) && !confirm(fmt.Sprintf("Track %s and install %s?", repoPath, release.GetTagName())) {
+			fmt.Println("Cancelled; nothing was added.")
+			return
+		}
+
+		if err := mgr.AddAndInstall(repoPath, newRepo, release); err != nil {
+			fmt.Printf("Error during initial install: %v\n", err)
 		}
 	},
 }
 
+// repoFromFlags builds the new repo's config from add's flags, applying the
+// global defaults for unset flags, and validates it against the tracked repos.
+func repoFromFlags(cmd *cobra.Command, repoPath string, cfg *config.Config) (*config.Repo, error) {
+	newRepo := &config.Repo{Path: repoPath, IncludePrerelease: cfg.Global.DefaultPrerelease}
+	if cmd.Flags().Changed("prerelease") {
+		newRepo.IncludePrerelease = flagPreRelease
+	}
+
+	fields := config.RepoFields()
+	set := func(key, value string) error {
+		f, err := config.FindField(fields, key)
+		if err != nil {
+			return err
+		}
+		return f.Set(newRepo, value)
+	}
+	for _, ff := range addFieldFlags {
+		if cmd.Flags().Changed(ff.flag) {
+			value, _ := cmd.Flags().GetString(ff.flag)
+			if err := set(ff.key, value); err != nil {
+				return nil, fmt.Errorf("--%s: %w", ff.flag, err)
+			}
+		}
+	}
+	for _, kv := range flagSet {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("--set %s: expected key=value", kv)
+		}
+		if err := set(key, value); err != nil {
+			return nil, fmt.Errorf("--set %s: %w", kv, err)
+		}
+	}
+	if newRepo.VersionCheck != nil && newRepo.VersionCheck.Strategy == "" {
+		newRepo.VersionCheck.Strategy = "regex"
+	}
+
+	if err := validateNewRepo(cfg, repoPath, newRepo); err != nil {
+		return nil, err
+	}
+	return newRepo, nil
+}
+
+// validateNewRepo reports the first error in the settings of a repo about
+// to be tracked, checked alongside the repos already tracked.
+func validateNewRepo(cfg *config.Config, repoPath string, newRepo *config.Repo) error {
+	check := &config.Config{Global: cfg.Global, Repos: map[string]*config.Repo{repoPath: newRepo}}
+	for k, r := range cfg.Repos {
+		if k != repoPath {
+			check.Repos[k] = r
+		}
+	}
+	prefix := fmt.Sprintf("repos[%q]", repoPath)
+	for _, issue := range check.Validate() {
+		if !issue.Warning && strings.HasPrefix(issue.Field, prefix) {
+			return fmt.Errorf("%s: %s", strings.TrimPrefix(issue.Field, prefix+"."), issue.Message)
+		}
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(addCmd)
-	addCmd.Flags().BoolVar(&flagPreRelease, "prerelease", false, "Include pre-releases when checking for updates")
+	addCmd.Flags().BoolVar(&flagPreRelease, "prerelease", false, "Include pre-releases when checking for updates (default: global default_prerelease)")
 	addCmd.Flags().StringVar(&flagToken, "token", "", "GitHub token for private repositories")
-	addCmd.Flags().StringVar(&flagFilter, "filter", "", "Regex to prefer a specific asset (e.g., '.*musl.*')")
-	addCmd.Flags().StringVar(&flagInstallName, "name", "", "Set a custom binary name for the executable")
+	for _, ff := range addFieldFlags {
+		addCmd.Flags().String(ff.flag, "", ff.usage)
+	}
+	addCmd.Flags().StringArrayVar(&flagSet, "set", nil, "Set any repo config key, as key=value (repeatable)")
+	addCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Install without asking for confirmation")
+	addCmd.Flags().Bool("dry-run", false, "Print the install plan without changing anything")
 }
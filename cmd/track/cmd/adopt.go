@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/adopt"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/sys"
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [binary...]",
+	Short: "Start tracking tools that were installed by hand",
+	Long: `Scans PATH for known binaries that track does not manage yet, reads their
+version from '<binary> --version' (or -V) and starts tracking the GitHub
+repository each one comes from, installing the same version.
+
+Binaries are mapped to repositories by a built-in table (rg, fd, bat, fzf,
+gh, jq, lazygit, ...); --map and --map-file add or override entries.
+
+Usage:
+  track adopt [binary...] [--replace] [--dry-run] [--yes]
+
+Examples:
+  track adopt --dry-run
+  track adopt rg fzf
+  track adopt --map mytool=acme/mytool --replace
+  track adopt --map-file ~/tools.map --dir /usr/local/bin
+
+Flags:
+  --map name=owner/repo   Map a binary to a repository (repeatable)
+  --map-file file         Read "name owner/repo" lines (# comments)
+  --dir dir               Scan only these directories instead of PATH (repeatable)
+  --latest                Install the latest release instead of the detected version
+  --replace               Replace each unmanaged binary with a link to the managed one
+  --dry-run               Show what would be adopted without changing anything
+  --yes, -y               Adopt without asking for confirmation
+
+Notes:
+- Only the first binary of a name on PATH is adopted; later copies are
+  reported as shadowed.
+- If no release matches the detected version, the latest release is
+  installed.
+- Without --replace the unmanaged binary stays where it is and may shadow
+  the managed link. A binary in ~/.local/bin is always moved aside, since
+  track links there.
+- Binaries moved aside are kept in <data_dir>/adopted and put back if the
+  install fails.`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for name := range adopt.Known {
+			if strings.HasPrefix(name, toComplete) {
+				names = append(names, name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		mapping, err := adoptMapping(cmd)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(args) > 0 {
+			only := make(map[string]string)
+			for _, name := range args {
+				repo, ok := mapping[name]
+				if !ok {
+					fmt.Printf("Error: no repository is known for '%s'; add one with --map %s=owner/repo\n", name, name)
+					os.Exit(1)
+				}
+				only[name] = repo
+			}
+			mapping = only
+		}
+
+		dirs, _ := cmd.Flags().GetStringArray("dir")
+		if len(dirs) == 0 {
+			dirs = filepath.SplitList(os.Getenv("PATH"))
+		}
+		var candidates []adopt.Candidate
+		for _, c := range adopt.Scan(dirs, mapping, mgr.Cfg.Global.DataDir) {
+			if _, tracked := mgr.Cfg.Repos[c.Repo]; tracked {
+				fmt.Printf("%s (%s) is already tracked; skipping %s.\n", c.Name, c.Repo, c.Path)
+				continue
+			}
+			c.Version, err = adopt.DetectVersion(c.Path, 5*time.Second)
+			if err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			}
+			candidates = append(candidates, c)
+		}
+		if len(candidates) == 0 {
+			fmt.Println("No unmanaged tools with a known repository were found.")
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Binary", "Path", "Version", "Repository"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		table.SetAutoWrapText(false)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, c := range candidates {
+			version := c.Version
+			if version == "" {
+				version = "unknown"
+			}
+			table.Append([]string{c.Name, c.Path, version, c.Repo})
+		}
+		table.Render()
+		for _, c := range candidates {
+			for _, s := range c.Shadowed {
+				fmt.Printf("Note: %s is shadowed by %s and will not be adopted.\n", s, c.Path)
+			}
+		}
+
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			fmt.Println("Dry run: nothing was tracked, installed or replaced.")
+			return
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		if !yes && sys.IsTerminal(os.Stdin) && !confirm(fmt.Sprintf("Adopt %d tools?", len(candidates))) {
+			fmt.Println("Cancelled; nothing was adopted.")
+			return
+		}
+
+		latest, _ := cmd.Flags().GetBool("latest")
+		replace, _ := cmd.Flags().GetBool("replace")
+		failed := 0
+		for _, c := range candidates {
+			fmt.Printf("--- %s (%s)\n", c.Name, c.Repo)
+			repoCfg := mgr.AdoptRepo(c)
+			var release *github.RepositoryRelease
+			if c.Version != "" && !latest {
+				if release, err = mgr.ReleaseForVersion(c.Repo, repoCfg, c.Version); err != nil {
+					fmt.Printf("%v; installing the latest release instead.\n", err)
+				}
+			}
+			if release == nil {
+				if release, err = mgr.LatestRelease(c.Repo, repoCfg); err != nil {
+					fmt.Printf("Failed to adopt %s: %v\n", c.Name, err)
+					failed++
+					continue
+				}
+			}
+			backup, err := mgr.Adopt(c, repoCfg, release, replace)
+			if err != nil {
+				fmt.Printf("Failed to adopt %s: %v\n", c.Name, err)
+				failed++
+				continue
+			}
+			if backup != "" {
+				fmt.Printf("The original %s was moved to %s.\n", c.Path, backup)
+			} else if !replace {
+				fmt.Printf("%s is still installed; remove it or use --replace so the managed version runs.\n", c.Path)
+			}
+		}
+		if failed > 0 {
+			fmt.Printf("%d of %d tools could not be adopted.\n", failed, len(candidates))
+			os.Exit(1)
+		}
+	},
+}
+
+// adoptMapping merges the built-in table with --map-file and --map.
+func adoptMapping(cmd *cobra.Command) (map[string]string, error) {
+	mapping := make(map[string]string, len(adopt.Known))
+	for k, v := range adopt.Known {
+		mapping[k] = v
+	}
+	if file, _ := cmd.Flags().GetString("map-file"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		extra, err := adopt.ParseMapping(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for k, v := range extra {
+			mapping[k] = v
+		}
+	}
+	entries, _ := cmd.Flags().GetStringArray("map")
+	for _, e := range entries {
+		name, repo, err := adopt.ParseMapEntry(e)
+		if err != nil {
+			return nil, fmt.Errorf("--map: %w", err)
+		}
+		mapping[name] = repo
+	}
+	return mapping, nil
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+	adoptCmd.Flags().StringArray("map", nil, "Map a binary to a repository, as name=owner/repo (repeatable)")
+	adoptCmd.Flags().String("map-file", "", "File of \"name owner/repo\" lines to add to the mapping")
+	adoptCmd.Flags().StringArray("dir", nil, "Scan these directories instead of PATH (repeatable)")
+	adoptCmd.Flags().Bool("latest", false, "Install the latest release instead of the detected version")
+	adoptCmd.Flags().Bool("replace", false, "Replace unmanaged binaries with links to the managed install")
+	adoptCmd.Flags().Bool("dry-run", false, "Show what would be adopted without changing anything")
+	adoptCmd.Flags().BoolP("yes", "y", false, "Adopt without asking for confirmation")
+}
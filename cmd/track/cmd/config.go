@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"runtime"
 
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"github.com/user/track/internal/config"
 )
@@ -17,6 +18,11 @@ var configCmd = &cobra.Command{
 
 Usage:
   track config
+  track config get <key> [--repo <repo>]
+  track config set <key> <value> [--repo <repo>]
+  track config unset <key> [--repo <repo>]
+  track config list [--repo <repo>]
+  track config validate
 
 Aliases:
   cfg
@@ -28,7 +34,8 @@ Examples:
 Notes:
 - This command opens the config file for manual editing.
 - You can set pre-release, filters, and other options here.
-- The config file is always stored in the global data directory.`,
+- The config file is stored in ~/.config/track ($XDG_CONFIG_HOME/track),
+  %LOCALAPPDATA%\track on Windows, or $TRACK_HOME if set.`,
 	Aliases: []string{"cfg"},
 	Run: func(cmd *cobra.Command, args []string) {
 		_, err := config.Get()
@@ -36,7 +43,7 @@ Notes:
 			fmt.Printf("Error loading config: %v\n", err)
 			return
 		}
-		path, err := getConfigPath()
+		path, err := config.Path()
 		if err != nil {
 			fmt.Printf("Error finding config path: %v\n", err)
 			return
@@ -47,36 +54,6 @@ Notes:
 	},
 }
 
-func getConfigPath() (string, error) {
-	if runtime.GOOS == "windows" {
-		localAppData := os.Getenv("LOCALAPPDATA")
-		if localAppData == "" {
-			return "", fmt.Errorf("LOCALAPPDATA not set")
-		}
-		return localAppData + string(os.PathSeparator) + "track" + string(os.PathSeparator) + "config.json", nil
-	} else {
-		dataDir, err := configDataPath()
-		if err != nil {
-			return "", err
-		}
-		return dataDir + string(os.PathSeparator) + "config.json", nil
-	}
-}
-
-func configDataPath() (string, error) {
-	dataDir, err := os.UserCacheDir()
-	if err != nil {
-		home, _ := os.UserHomeDir()
-		dataDir = home + string(os.PathSeparator) + ".local" + string(os.PathSeparator) + "share" + string(os.PathSeparator) + "track"
-	} else {
-		dataDir = dataDir + string(os.PathSeparator) + "track"
-	}
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		return "", err
-	}
-	return dataDir, nil
-}
-
 func openEditor(path string) bool {
 	var cmd *exec.Cmd
 	if runtime.GOOS == "windows" {
@@ -96,6 +73,265 @@ func openEditor(path string) bool {
 	return true
 }
 
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the config file for errors",
+	Long: `Checks config.json (or the given file) without changing it.
+
+Reports:
+- JSON syntax and type errors, with line and column
+- Unknown fields, which track ignores, with a suggestion for likely typos
+- Invalid regexes (asset_filter, asset_exclude, default_asset_filter,
+  excluded_patterns, version_check.regex)
+- Unknown matcher_mode, source and version_check.strategy values
+- Missing url_template/version_check for url and tags sources
+
+Usage:
+  track config validate [file]
+
+Examples:
+  track config validate
+  track config validate ~/dotfiles/track.json
+
+Notes:
+- Exits with status 1 if there are errors. Warnings alone exit 0.
+- Configs from older track versions are upgraded automatically when loaded;
+  the previous file is kept as config.json.1.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var path string
+		if len(args) == 1 {
+			path = args[0]
+		} else {
+			var err error
+			if path, err = config.Path(); err != nil {
+				fmt.Printf("Error finding config path: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		issues, err := config.Check(data)
+		if err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+			os.Exit(1)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s: OK\n", path)
+			return
+		}
+		for _, issue := range issues {
+			fmt.Printf("%s: %s\n", path, issue)
+		}
+		if config.HasErrors(issues) {
+			os.Exit(1)
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key> [--repo <repo>]",
+	Short: "Print the effective value of a config key",
+	Long: `Prints the value of a global key, or of a repo key with --repo. Unset repo keys
+print the global default they inherit, e.g. matcher_mode or asset_priority.
+
+Usage:
+  track config get <key> [--repo <repo>]
+
+Examples:
+  track config get backup_count
+  track config get matcher_mode --repo BurntSushi/ripgrep
+  track config get version_check.strategy --repo hashicorp/terraform
+
+Notes:
+- Keys are the JSON names from config.json; nested keys are dotted.
+- Case, underscores and dashes are ignored: AssetFilter and asset-filter work too.
+- Run 'track config list' to see every key.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, repoPath, ok := configScope(cmd)
+		if !ok {
+			os.Exit(1)
+		}
+		s, err := cfg.Setting(repoPath, args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(s.Value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value> [--repo <repo>]",
+	Short: "Set a global or repo config key",
+	Long: `Sets a global key, or a repo key with --repo. The value is parsed according to
+the key's type and checked before saving, so an invalid regex or unknown
+matcher_mode is rejected instead of silently ignored.
+
+Usage:
+  track config set <key> <value> [--repo <repo>]
+
+Examples:
+  track config set backup_count 5
+  track config set excluded_patterns '\.deb$,\.rpm$,checksums'
+  track config set default_asset_priority '["x86_64","amd64"]'
+  track config set asset_filter '.*musl.*' --repo BurntSushi/ripgrep
+  track config set version_check.strategy redirect --repo hashicorp/terraform
+
+Notes:
+- Lists are comma-separated or a JSON array. Booleans are true/false.
+- Objects such as build and version_check are set key by key
+  (build.command) or whole as a JSON object.
+- current_version and version_history are maintained by track and read-only.
+- Changing data_dir does not move installed tools; use 'track migrate-data'.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, repoPath, ok := configScope(cmd)
+		if !ok {
+			os.Exit(1)
+		}
+		if !setConfigKey(cfg, repoPath, args[0], args[1]) {
+			os.Exit(1)
+		}
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key> [--repo <repo>]",
+	Short: "Reset a global or repo config key to its default",
+	Long: `Resets a global key, or a repo key with --repo, to its default. Unset repo keys
+fall back to the matching global default where there is one.
+
+Usage:
+  track config unset <key> [--repo <repo>]
+
+Examples:
+  track config unset default_asset_filter
+  track config unset asset_priority --repo BurntSushi/ripgrep
+  track config unset build --repo acme/tool`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, repoPath, ok := configScope(cmd)
+		if !ok {
+			os.Exit(1)
+		}
+		key := args[0]
+		if err := unsetConfigKey(cfg, repoPath, key); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("Error saving config: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list [--repo <repo>]",
+	Short: "List the effective value of every config key",
+	Long: `Lists every global key, or every key of a repo with --repo, with its effective
+value and where it comes from.
+
+Usage:
+  track config list [--repo <repo>]
+
+Examples:
+  track config list
+  track config list --repo BurntSushi/ripgrep`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, repoPath, ok := configScope(cmd)
+		if !ok {
+			os.Exit(1)
+		}
+		settings, err := cfg.Settings(repoPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Key", "Type", "Value", "Source"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		table.SetAutoWrapText(false)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, s := range settings {
+			source := s.Origin
+			switch {
+			case s.State:
+				source = "state"
+			case source == "":
+				source = "set"
+			case source != "default":
+				source = "inherited from " + source
+			}
+			table.Append([]string{s.Key, s.Kind, s.Value, source})
+		}
+		table.Render()
+	},
+}
+
+// configScope loads the config and resolves the --repo flag, "" meaning
+// the global settings.
+func configScope(cmd *cobra.Command) (*config.Config, string, bool) {
+	cfg, err := config.Get()
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		return nil, "", false
+	}
+	arg, _ := cmd.Flags().GetString("repo")
+	if arg == "" {
+		return cfg, "", true
+	}
+	repoPath, err := resolveRepo(cfg, arg)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return nil, "", false
+	}
+	return cfg, repoPath, true
+}
+
+// setConfigKey sets and saves one key, printing hints about related
+// settings that are still incomplete.
+func setConfigKey(cfg *config.Config, repoPath, key, value string) bool {
+	hints, err := cfg.SetField(repoPath, key, value)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return false
+	}
+	if err := cfg.Save(); err != nil {
+		fmt.Printf("Error saving config: %v\n", err)
+		return false
+	}
+	for _, h := range hints {
+		fmt.Printf("Note: %s\n", h)
+	}
+	if f, err := config.FindField(config.GlobalFields(), key); err == nil && repoPath == "" && f.Key == "data_dir" {
+		fmt.Println("Note: installed tools were not moved; 'track migrate-data <dir>' moves them and updates data_dir.")
+	}
+	return true
+}
+
+func unsetConfigKey(cfg *config.Config, repoPath, key string) error {
+	if repoPath == "" {
+		if f, err := config.FindField(config.GlobalFields(), key); err == nil && f.Key == "data_dir" {
+			return fmt.Errorf("data_dir cannot be unset; use 'track migrate-data' to move installed tools")
+		}
+	}
+	return cfg.UnsetField(repoPath, key)
+}
+
 func init() {
+	for _, c := range []*cobra.Command{configGetCmd, configSetCmd, configUnsetCmd, configListCmd} {
+		c.Flags().String("repo", "", "Repository (owner/repo, name or alias) instead of the global settings")
+		c.RegisterFlagCompletionFunc("repo", completeRepos)
+		configCmd.AddCommand(c)
+	}
+	configCmd.AddCommand(configValidateCmd)
 	rootCmd.AddCommand(configCmd)
 }
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/manager"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the config, installs, links and PATH for problems",
+	Long: `Audits the track setup and reports anything that would stop installed tools
+from running:
+
+  config        config.json problems, e.g. invalid asset_filter regexes
+  github token  whether GITHUB_TOKEN is set
+  data dir      whether data_dir exists and is writable
+  PATH          whether ~/.local/bin (on Windows <data_dir>/latest) is on PATH
+  installs      current_version folders that are missing or hold no executable,
+                and executables without the executable bit
+  links         missing, dangling or stale links for tracked repos, and links
+                left pointing into data_dir by removed repos
+  shadowing     tracked tools that another install earlier on PATH hides
+
+Usage:
+  track doctor [--fix]
+
+Examples:
+  track doctor
+  track doctor --fix
+
+Flags:
+  --fix   Repair what can be repaired: reinstall missing versions, restore the
+          executable bit, recreate links and delete dangling ones
+
+Notes:
+- A regular file where a link belongs is left alone; move it away and run
+  'track doctor --fix' again to link the managed install instead.
+- Warnings (a missing token, a shadowed tool) do not affect the exit status.
+- Exits with status 1 if problems remain, after --fix if given.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		checks := mgr.Diagnose()
+		printChecks(checks)
+		if fix {
+			fixed := 0
+			for _, check := range checks {
+				for _, f := range check.Findings {
+					if !f.Fixable() {
+						continue
+					}
+					fmt.Printf("Fixing: %s (%s)\n", f.Problem, f.FixDesc)
+					if err := f.Fix(); err != nil {
+						fmt.Printf("Fix failed: %v\n", err)
+						continue
+					}
+					fixed++
+				}
+			}
+			if fixed > 0 {
+				fmt.Printf("\nApplied %d fixes. Checking again:\n", fixed)
+				checks = mgr.Diagnose()
+				printChecks(checks)
+			}
+		}
+
+		problems, warnings, fixable := 0, 0, 0
+		for _, check := range checks {
+			for _, f := range check.Findings {
+				switch {
+				case f.Warning:
+					warnings++
+				default:
+					problems++
+					if f.Fixable() {
+						fixable++
+					}
+				}
+			}
+		}
+		switch {
+		case problems == 0 && warnings == 0:
+			fmt.Println("No problems found.")
+		case problems == 0:
+			fmt.Printf("No problems found, %d warnings.\n", warnings)
+		default:
+			fmt.Printf("%d problems, %d warnings.", problems, warnings)
+			if fixable > 0 && !fix {
+				fmt.Printf(" Run 'track doctor --fix' to repair %d of them.", fixable)
+			}
+			fmt.Println()
+			os.Exit(1)
+		}
+	},
+}
+
+// printChecks prints one line per check, followed by its findings.
+func printChecks(checks []manager.Check) {
+	for _, check := range checks {
+		if len(check.Findings) == 0 {
+			fmt.Printf("[ok]   %s\n", check.Name)
+			continue
+		}
+		for _, f := range check.Findings {
+			label := "[FAIL]"
+			if f.Warning {
+				label = "[warn]"
+			}
+			fmt.Printf("%s %s: %s\n", label, check.Name, f.Problem)
+			switch {
+			case f.Fixable():
+				fmt.Printf("       fix: %s (track doctor --fix)\n", f.FixDesc)
+			case f.Hint != "":
+				fmt.Printf("       hint: %s\n", f.Hint)
+			}
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().Bool("fix", false, "Repair the problems that can be repaired automatically")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/manager"
+)
+
+var duCmd = &cobra.Command{
+	Use:   "du [repo...]",
+	Short: "Show disk usage per repository and version",
+	Long: `Shows how much space each installed version takes in data_dir, the total
+per repository, and directories left behind by repositories that are no
+longer tracked.
+
+Usage:
+  track du [repo...]
+
+Examples:
+  track du
+  track du rg
+  track du --tag k8s
+
+Notes:
+- The current version is marked with *.
+- Space held by old versions is freed by 'track tidy', and space held by
+  orphaned directories by 'track tidy --orphans'.
+- Orphaned directories are only listed, and counted in the total, when no
+  repository or --tag is given.
+` + refsHelp,
+	ValidArgsFunction: completeRepos,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		repos, err := resolveRepos(mgr.Cfg, args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Repository", "Version", "Size", "Installed"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		table.SetAutoWrapText(false)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+		var total, old int64
+		for _, repoPath := range repos {
+			current := mgr.Cfg.Repos[repoPath].CurrentVersion
+			dirs := mgr.VersionDirs(repoPath)
+			var repoTotal int64
+			for i, dir := range dirs {
+				name, version := "", dir.Version
+				if i == 0 {
+					name = repoPath
+				}
+				if version == current {
+					version += " *"
+				} else {
+					old += dir.Bytes
+				}
+				table.Append([]string{name, version, formatBytes(dir.Bytes), dir.Modified.Format("2006-01-02")})
+				repoTotal += dir.Bytes
+			}
+			if len(dirs) > 1 {
+				table.Append([]string{"", "total", formatBytes(repoTotal), ""})
+			}
+			total += repoTotal
+		}
+		table.Render()
+
+		// Orphans belong to no repository, so only the full report has them.
+		var orphaned int64
+		if len(args) == 0 && len(flagTags) == 0 {
+			if orphans := mgr.Orphans(); len(orphans) > 0 {
+				fmt.Println("\nOrphaned directories (no tracked repository):")
+				for _, dir := range orphans {
+					fmt.Printf("  %s  %s\n", dir.Path, formatBytes(dir.Bytes))
+					orphaned += dir.Bytes
+				}
+			}
+		}
+
+		fmt.Printf("\nTotal: %s", formatBytes(total+orphaned))
+		if old > 0 {
+			fmt.Printf(", %s in old versions ('track tidy')", formatBytes(old))
+		}
+		if orphaned > 0 {
+			fmt.Printf(", %s orphaned ('track tidy --orphans')", formatBytes(orphaned))
+		}
+		fmt.Println(".")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(duCmd)
+	addTagFlag(duCmd)
+}
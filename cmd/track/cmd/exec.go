@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/manager"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec <owner/repo>[@tag] [-- args...]",
+	Short: "Run a specific version of a tracked tool without changing the current link",
+	Long: `Runs the given version of a tracked repository's executable with the remaining arguments. The version is downloaded on demand into the usual versioned data directory; the current version and its links are not changed.
+
+Usage:
+  track exec <owner/repo>@<tag> -- [args...]
+  track exec <owner/repo> -- [args...]     # current version
+
+Examples:
+  track exec BurntSushi/ripgrep@14.0.0 -- --version
+  track exec jesseduffield/lazygit@v0.40.0
+  track exec rg@14.0.0 -- --version
+
+Notes:
+- The exit code of the tool is passed through. Download progress goes to stderr, so stdout carries only the tool's output.
+- The part before @ can be owner/repo, an install name, an alias or a unique prefix of a tracked repository.
+- Set 'link_versions': true in the global config to also get name@version links for every installed version.`,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			cmd.Help()
+			return
+		}
+		ref, version, _ := strings.Cut(args[0], "@")
+		toolArgs := args[1:]
+		if len(toolArgs) > 0 && toolArgs[0] == "--" {
+			toolArgs = toolArgs[1:]
+		}
+
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		repoPath, err := resolveRepo(mgr.Cfg, ref)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		repoCfg := mgr.Cfg.Repos[repoPath]
+		if version == "" {
+			version = repoCfg.CurrentVersion
+		}
+		if version == "" {
+			fmt.Fprintf(os.Stderr, "Error: %s is not installed; specify a version as %s@<tag>.\n", repoPath, repoPath)
+			os.Exit(1)
+		}
+
+		var exe string
+		progressToStderr(func() { exe, err = mgr.EnsureVersion(repoPath, version) })
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(runBinary(exe, toolArgs))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/fsutil"
+	"github.com/user/track/internal/portable"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [repo...]",
+	Short: "Write tracked repositories and settings to a portable file",
+	Long: `Writes the settings of every tracked repository (or the ones named) and the global settings as JSON, for 'track import' on another machine.
+
+Installed versions, version history and data_dir are left out, so the file
+describes what to track rather than the state of this machine.
+
+Usage:
+  track export [repo...] [-o file]
+
+Examples:
+  track export > tools.json
+  track export -o tools.json
+  track export --tag work -o work-tools.json
+
+Notes:
+` + refsHelp,
+	ValidArgsFunction: completeRepos,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Get()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		repos, err := resolveRepos(cfg, args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		data, err := portable.Export(cfg, repos)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		out, _ := cmd.Flags().GetString("output")
+		if out == "" || out == "-" {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := fsutil.WriteFileAtomic(out, data, 0644); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Exported %d repositories to %s.\n", len(repos), out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	addTagFlag(exportCmd)
+	exportCmd.Flags().StringP("output", "o", "", "Write to this file instead of stdout")
+}
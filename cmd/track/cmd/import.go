@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/portable"
+	"github.com/user/track/internal/sys"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file|->",
+	Short: "Track and install repositories listed in a file",
+	Long: `Adds every repository in the file to the tracked list and installs it.
+
+Accepted formats, detected from the file name or content:
+  track export JSON   Repo settings and global settings (a config.json works too)
+  plain list          One owner/repo per line, optionally owner/repo@tag; # comments
+  .eget.toml          eget's config: [owner/repo] tables with asset_filters and tag
+  aqua.yaml           The packages list: "- name: owner/repo@version"
+
+Usage:
+  track import <file|-> [--dry-run] [--no-install] [--no-global] [--yes]
+
+Examples:
+  track import tools.json
+  track export | ssh laptop track import -
+  track import ~/.eget.toml
+  track import aqua.yaml --no-install
+
+Notes:
+- Repositories that are already tracked are skipped.
+- A version pinned by the file (@tag, eget's tag, aqua's version) is
+  installed instead of the latest release; later updates move to the latest.
+- Global settings from an export are applied unless --no-global is given.
+  data_dir is never imported.
+- If an install fails, that repository is not added; the others continue.
+- Settings of other formats that track cannot express are listed as notes.
+- Exits with status 1 if any repository could not be imported.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		var data []byte
+		var err error
+		if name == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(name)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		imp, err := portable.Parse(name, data)
+		if err != nil {
+			fmt.Printf("Error: %s: %v\n", name, err)
+			os.Exit(1)
+		}
+
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		noInstall, _ := cmd.Flags().GetBool("no-install")
+		noGlobal, _ := cmd.Flags().GetBool("no-global")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		var pending []portable.Entry
+		for _, e := range imp.Entries {
+			if _, tracked := mgr.Cfg.Repos[e.Path]; tracked {
+				fmt.Printf("%s is already tracked; skipping.\n", e.Path)
+				continue
+			}
+			pending = append(pending, e)
+		}
+		for _, s := range imp.Skipped {
+			fmt.Printf("Skipping %s\n", s)
+		}
+
+		fmt.Printf("Importing %d repositories from %s (%s format):\n", len(pending), name, imp.Format)
+		for _, e := range pending {
+			version := "latest"
+			if e.Tag != "" {
+				version = e.Tag
+			}
+			fmt.Printf("  %s (%s)\n", e.Path, version)
+			for _, n := range e.Notes {
+				fmt.Printf("    note: %s\n", n)
+			}
+		}
+		if dryRun {
+			fmt.Println("Dry run: nothing was added or installed.")
+			return
+		}
+		if len(pending) > 0 && !yes && sys.IsTerminal(os.Stdin) && !confirm("Continue?") {
+			fmt.Println("Cancelled; nothing was imported.")
+			return
+		}
+
+		failed := 0
+		if !noGlobal && len(imp.Global) > 0 {
+			if err := importGlobal(mgr.Cfg, imp.Global); err != nil {
+				fmt.Printf("Error: global settings: %v\n", err)
+				failed++
+			}
+		}
+
+		for _, e := range pending {
+			if err := importEntry(mgr, e, noInstall); err != nil {
+				fmt.Printf("Failed to import %s: %v\n", e.Path, err)
+				failed++
+			}
+		}
+		if failed > 0 {
+			fmt.Printf("%d of %d imports failed.\n", failed, len(pending))
+			os.Exit(1)
+		}
+	},
+}
+
+// importGlobal applies imported global settings, printing each one that
+// changes, and saves the config.
+func importGlobal(cfg *config.Config, values map[string]string) error {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	changed := false
+	for _, key := range keys {
+		before, _ := cfg.Setting("", key)
+		if _, err := cfg.SetField("", key, values[key]); err != nil {
+			fmt.Printf("Skipping global %s: %v\n", key, err)
+			continue
+		}
+		if after, _ := cfg.Setting("", key); after.Value != before.Value {
+			fmt.Printf("Set global %s = %s\n", after.Key, after.Value)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return cfg.Save()
+}
+
+// importEntry tracks one repository and installs its pinned or latest release.
+func importEntry(mgr *manager.Manager, e portable.Entry, noInstall bool) error {
+	if err := validateNewRepo(mgr.Cfg, e.Path, e.Repo); err != nil {
+		return err
+	}
+	if noInstall {
+		return mgr.AddRepo(e.Path, e.Repo)
+	}
+
+	var release *github.RepositoryRelease
+	var err error
+	if e.Tag != "" {
+		release, err = mgr.ReleaseByTag(e.Path, e.Repo, e.Tag)
+	} else {
+		release, err = mgr.LatestRelease(e.Path, e.Repo)
+	}
+	if err != nil {
+		return err
+	}
+	return mgr.AddAndInstall(e.Path, e.Repo, release)
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().Bool("dry-run", false, "Show what would be imported without changing anything")
+	importCmd.Flags().Bool("no-install", false, "Only track the repositories; install later with 'track update'")
+	importCmd.Flags().Bool("no-global", false, "Ignore global settings in the file")
+	importCmd.Flags().BoolP("yes", "y", false, "Import without asking for confirmation")
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hako/durafmt"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/gh"
+	"github.com/user/track/internal/manager"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info <owner/repo|repo>",
+	Short: "Show repository metadata, latest release and install state",
+	Long: `Shows GitHub metadata, license, the latest release, the asset that would be installed on this platform, and the current install state of a repository. The repository does not need to be tracked.
+
+Usage:
+  track info <owner/repo>
+  track info <repo>
+
+Examples:
+  track info BurntSushi/ripgrep
+  track info rg
+
+Notes:
+- Any owner/repo works; other references must name a tracked repository,
+  and --tag can only select tracked ones.
+` + refsHelp,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		repoPath, err := resolveAnyRepo(mgr.Cfg, args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		owner, name, ok := strings.Cut(repoPath, "/")
+		if !ok {
+			fmt.Println("Error: Invalid repository format. Please use 'owner/repo'.")
+			return
+		}
+
+		repoCfg, tracked := mgr.Cfg.Repos[repoPath]
+		if !tracked {
+			repoCfg = &config.Repo{}
+		}
+
+		ctx := context.Background()
+		client := gh.NewClient(ctx, "")
+		printField := func(label, value string) {
+			if value != "" {
+				fmt.Printf("%-16s %s\n", label+":", value)
+			}
+		}
+
+		if repo, err := client.GetRepo(ctx, owner, name); err != nil {
+			fmt.Printf("GitHub metadata unavailable: %v\n", err)
+		} else {
+			printField("Repository", repo.GetFullName())
+			printField("Description", repo.GetDescription())
+			printField("Homepage", repo.GetHomepage())
+			printField("Stars", strconv.Itoa(repo.GetStargazersCount()))
+			printField("Forks", strconv.Itoa(repo.GetForksCount()))
+			printField("Open issues", strconv.Itoa(repo.GetOpenIssuesCount()))
+			license := "none"
+			if repo.GetLicense() != nil {
+				license = repo.GetLicense().GetName()
+			}
+			printField("License", license)
+			printField("Default branch", repo.GetDefaultBranch())
+			if repo.GetArchived() {
+				printField("Archived", "yes")
+			}
+		}
+		if repoCfg.Source != "" {
+			printField("Source", repoCfg.Source)
+		}
+		fmt.Println()
+
+		release, err := mgr.LatestRelease(repoPath, repoCfg)
+		if err != nil {
+			printField("Latest release", fmt.Sprintf("unavailable (%v)", err))
+		} else {
+			latest := release.GetTagName()
+			if !release.GetPublishedAt().IsZero() {
+				latest += fmt.Sprintf(" (published %s ago)", durafmt.ParseShort(time.Since(release.GetPublishedAt().Time)))
+			}
+			printField("Latest release", latest)
+			if asset, err := mgr.SelectAsset(repoCfg, release); err != nil {
+				printField("Selected asset", fmt.Sprintf("none (%v)", err))
+			} else {
+				printField("Selected asset", asset.GetName())
+			}
+		}
+		fmt.Println()
+
+		if !tracked {
+			printField("Tracked", "no")
+			return
+		}
+		printField("Tracked", "yes")
+		if f := repoCfg.LastFailure; f != nil {
+			printField("Last failure", fmt.Sprintf("%s at %s: %s", f.Version, f.Time, f.Error))
+		}
+		if repoCfg.CurrentVersion == "" {
+			printField("Installed", "no")
+			return
+		}
+		printField("Current version", repoCfg.CurrentVersion)
+		versionDir := mgr.VersionDir(repoPath, repoCfg.CurrentVersion)
+		if _, err := os.Stat(versionDir); err != nil {
+			printField("Install dir", versionDir+" (missing)")
+		} else {
+			printField("Install dir", versionDir)
+		}
+		link := filepath.Join(mgr.Cfg.Global.DataDir, "latest", mgr.InstallName(repoPath, repoCfg))
+		if target, err := os.Readlink(link); err == nil {
+			printField("Link", link+" -> "+target)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+	addTagFlag(infoCmd)
+	infoCmd.ValidArgsFunction = completeRepo
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/project"
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install the tool versions declared in the nearest project manifest",
+	Long: `Finds the nearest .track.json or .track.toml (in the current directory or a parent) and installs every tool version it declares into the usual versioned data directory.
+
+Usage:
+  track install
+
+Manifest examples:
+  .track.json:
+    {"tools": {"BurntSushi/ripgrep": "14.1.0", "junegunn/fzf": "v0.44.1"}}
+
+  .track.toml:
+    [tools]
+    "BurntSushi/ripgrep" = "14.1.0"
+
+Notes:
+- Tools that are not tracked yet are added to the config; if their install fails, they are removed again.
+- The global current version of a tool is only set if it has none yet.
+- Enable shim mode ('shim_mode': true in the global config) so that each tool runs the version from the nearest manifest, falling back to the global current version.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		manifest, err := project.Find(cwd)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if manifest == nil {
+			fmt.Printf("No %s or %s found in %s or its parents.\n", project.JSONFile, project.TOMLFile, cwd)
+			return
+		}
+		fmt.Printf("Installing tools from %s\n", manifest.Path)
+
+		repos := make([]string, 0, len(manifest.Tools))
+		for k := range manifest.Tools {
+			repos = append(repos, k)
+		}
+		sort.Strings(repos)
+
+		for _, repoPath := range repos {
+			version := manifest.Tools[repoPath]
+			if _, ok := mgr.Cfg.Repos[repoPath]; !ok {
+				// A tool whose first install fails is not left tracked.
+				if err := mgr.AddAndInstallTag(repoPath, nil, version); err != nil {
+					fmt.Printf("Failed to install %s %s: %v\n", repoPath, version, err)
+				}
+				continue
+			}
+			repoCfg := mgr.Cfg.Repos[repoPath]
+
+			if repoCfg.CurrentVersion == "" {
+				// Nothing links this tool yet, so the manifest version becomes current.
+				release, err := mgr.ReleaseByTag(repoPath, repoCfg, version)
+				if err == nil {
+					err = mgr.InstallVersion(repoPath, release)
+				}
+				if err != nil {
+					fmt.Printf("Failed to install %s %s: %v\n", repoPath, version, err)
+				}
+				continue
+			}
+
+			exe, err := mgr.EnsureVersion(repoPath, version)
+			if err != nil {
+				fmt.Printf("Failed to install %s %s: %v\n", repoPath, version, err)
+				continue
+			}
+			fmt.Printf("%s %s is installed at %s\n", repoPath, version, exe)
+			if mgr.Cfg.Global.ShimMode {
+				if err := mgr.Relink(repoPath); err != nil {
+					fmt.Printf("Failed to create shims for %s: %v\n", repoPath, err)
+				}
+			}
+		}
+		if !mgr.Cfg.Global.ShimMode {
+			fmt.Println("Note: shim_mode is off, so links still point at the global current versions.")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(installCmd)
+}
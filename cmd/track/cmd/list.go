@@ -3,8 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"sort"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -25,8 +26,19 @@ Aliases:
 Examples:
   track list
   track ls
+  track list --tag work
+  track list --flat
 
-This command displays a table of all tracked repositories. If none are tracked, it will prompt you to add one.`,
+This command displays a table of all tracked repositories. If none are tracked, it will prompt you to add one.
+
+The Names column lists the repo name, install name and aliases; any of
+them can be used instead of owner/repo in other commands. The # column
+is only a shortcut for typing in a terminal: the numbers shift whenever a
+repository is added, so scripts should use names.
+
+When any repository has tags, the list is grouped by tag; a repository
+with several tags appears in each group. --flat prints a single table, and
+--tag shows only the selected repositories.`,
 	Aliases: []string{"ls", "status"},
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg, err := config.Get()
@@ -40,35 +52,75 @@ This command displays a table of all tracked repositories. If none are tracked,
 			return
 		}
 
-		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"#", "Repository", "Current Version", "Pre-release", "Filter"})
-		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
-		table.SetCenterSeparator("|")
-
-		keys := make([]string, 0, len(cfg.Repos))
-		for k := range cfg.Repos {
-			keys = append(keys, k)
+		keys := cfg.SortedRepos()
+		selected, err := resolveRepos(cfg, nil)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
 		}
-		sort.Strings(keys)
-
+		numbers := make(map[string]int, len(keys))
 		for i, k := range keys {
-			repo := cfg.Repos[k]
-			version := repo.CurrentVersion
-			if version == "" {
-				version = "Not installed"
+			numbers[k] = i + 1
+		}
+		printTable := func(repos []string) {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetHeader([]string{"#", "Repository", "Names", "Current Version", "Pre-release", "Filter"})
+			table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+			table.SetCenterSeparator("|")
+			for _, k := range repos {
+				repo := cfg.Repos[k]
+				version := repo.CurrentVersion
+				if version == "" {
+					version = "Not installed"
+				}
+				table.Append([]string{
+					strconv.Itoa(numbers[k]),
+					k,
+					strings.Join(cfg.RepoNames(k), ", "),
+					version,
+					fmt.Sprintf("%t", repo.IncludePrerelease),
+					repo.AssetFilter,
+				})
+			}
+			table.Render()
+		}
+
+		tags := cfg.Tags()
+		if flat, _ := cmd.Flags().GetBool("flat"); flat || len(tags) == 0 {
+			printTable(selected)
+			return
+		}
+		sel, _ := config.ParseTagSelector(flagTags)
+		first := true
+		printGroup := func(title string, repos []string) {
+			if len(repos) == 0 {
+				return
+			}
+			if !first {
+				fmt.Println()
+			}
+			first = false
+			fmt.Printf("%s (%d)\n", title, len(repos))
+			printTable(repos)
+		}
+		for _, tag := range tags {
+			if len(sel.Include) > 0 && !slices.Contains(sel.Include, tag) {
+				continue
+			}
+			printGroup(tag, cfg.Select(selected, config.TagSelector{Include: []string{tag}}))
+		}
+		var untagged []string
+		for _, k := range selected {
+			if len(cfg.Repos[k].Tags) == 0 {
+				untagged = append(untagged, k)
 			}
-			table.Append([]string{
-				strconv.Itoa(i + 1),
-				k,
-				version,
-				fmt.Sprintf("%t", repo.IncludePrerelease),
-				repo.AssetFilter,
-			})
 		}
-		table.Render()
+		printGroup("untagged", untagged)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+	addTagFlag(listCmd)
+	listCmd.Flags().Bool("flat", false, "Print one table instead of grouping by tag")
 }
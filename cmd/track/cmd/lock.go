@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/lockfile"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/portable"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock [repo...]",
+	Short: "Record the exact tag, asset and sha256 of every tracked tool in track.lock",
+	Long: `Writes a lockfile pinning each tracked repository to an exact tag, asset name, download URL and sha256 digest for this platform. Use 'track sync' to install exactly what the lockfile specifies on any machine.
+
+Usage:
+  track lock                  # lock installed versions of repos not yet in the lockfile
+  track lock --update         # re-lock every repo to its latest release
+  track lock --update rg      # re-lock only ripgrep
+  track lock --file ./track.lock
+
+Flags:
+  -u, --update   Refresh entries to the latest release
+  --file         Lockfile path (default: track.lock next to config.json)
+
+Notes:
+- Without --update, existing entries are kept; repos are locked at their current version, or the latest release if not installed.
+- Assets are recorded per platform. Run 'track lock' on each OS/arch that shares the lockfile to add its asset for the same tag.
+- Locking all repos drops entries for repos that are no longer tracked.
+` + refsHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		path, err := lockfilePath(cmd)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		lock, err := lockfile.Load(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		repos, err := resolveRepos(mgr.Cfg, args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(args) == 0 {
+			for repoPath := range lock.Repos {
+				if _, ok := mgr.Cfg.Repos[repoPath]; !ok {
+					fmt.Printf("Dropping %s (no longer tracked).\n", repoPath)
+					delete(lock.Repos, repoPath)
+				}
+			}
+		}
+
+		update, _ := cmd.Flags().GetBool("update")
+		platform := lockfile.Platform()
+		for _, repoPath := range repos {
+			repoCfg := mgr.Cfg.Repos[repoPath]
+			entry := lock.Repos[repoPath]
+			if !update && entry != nil && entry.Assets[platform] != nil {
+				if err := lockSettings(entry, repoCfg); err != nil {
+					fmt.Printf("Failed to lock %s: %v\n", repoPath, err)
+				}
+				continue
+			}
+
+			var tag string
+			switch {
+			case update:
+			case entry != nil:
+				tag = entry.Tag
+			default:
+				tag = repoCfg.CurrentVersion
+			}
+			var release *github.RepositoryRelease
+			if tag == "" {
+				release, err = mgr.LatestRelease(repoPath, repoCfg)
+			} else {
+				release, err = mgr.ReleaseByTag(repoPath, repoCfg, tag)
+			}
+			if err != nil {
+				fmt.Printf("Failed to lock %s: %v\n", repoPath, err)
+				continue
+			}
+
+			asset, err := mgr.LockAsset(repoPath, repoCfg, release)
+			if err != nil {
+				fmt.Printf("Failed to lock %s: %v\n", repoPath, err)
+				continue
+			}
+			if entry == nil || entry.Tag != release.GetTagName() {
+				if entry != nil && lockedElsewhere(entry, platform) {
+					fmt.Printf("Note: %s moved to %s; assets locked for other platforms were dropped.\n", repoPath, release.GetTagName())
+				}
+				entry = &lockfile.Entry{Tag: release.GetTagName(), Assets: make(map[string]*lockfile.Asset)}
+				lock.Repos[repoPath] = entry
+			}
+			if err := lockSettings(entry, repoCfg); err != nil {
+				fmt.Printf("Failed to lock %s: %v\n", repoPath, err)
+				continue
+			}
+			entry.Assets[platform] = asset
+			fmt.Printf("Locked %s at %s (%s).\n", repoPath, entry.Tag, asset.Name)
+		}
+
+		if err := lock.Save(path); err != nil {
+			fmt.Printf("Error saving lockfile: %v\n", err)
+			return
+		}
+		fmt.Printf("Wrote %s.\n", path)
+	},
+}
+
+// lockSettings records the repo's settings in entry, so that sync can add
+// the repo as it is tracked here.
+func lockSettings(entry *lockfile.Entry, repoCfg *config.Repo) error {
+	settings, err := portable.RepoSettings(repoCfg)
+	if err != nil {
+		return err
+	}
+	entry.InstallName = repoCfg.InstallName
+	entry.Repo = settings
+	return nil
+}
+
+// lockedElsewhere reports whether entry has assets for platforms other than platform.
+func lockedElsewhere(entry *lockfile.Entry, platform string) bool {
+	for p := range entry.Assets {
+		if p != platform {
+			return true
+		}
+	}
+	return false
+}
+
+// lockfilePath returns the --file flag or the default lockfile location.
+func lockfilePath(cmd *cobra.Command) (string, error) {
+	if path, _ := cmd.Flags().GetString("file"); path != "" {
+		return path, nil
+	}
+	return lockfile.DefaultPath()
+}
+
+func init() {
+	rootCmd.AddCommand(lockCmd)
+	addTagFlag(lockCmd)
+	lockCmd.ValidArgsFunction = completeRepos
+	lockCmd.Flags().BoolP("update", "u", false, "Refresh entries to the latest release")
+	lockCmd.Flags().String("file", "", "Lockfile path (default: track.lock next to config.json)")
+}
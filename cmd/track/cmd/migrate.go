@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/paths"
+)
+
+var migrateDataCmd = &cobra.Command{
+	Use:   "migrate-data [newdir]",
+	Short: "Move installed tools to a new data directory",
+	Long: `Moves every installed version from the current data_dir to newdir, repoints the links in the latest folder and ~/.local/bin (or the .cmd shims on Windows) and updates data_dir in the config.
+
+Without newdir, tools are moved to the default data directory:
+~/.local/share/track ($XDG_DATA_HOME/track), %LOCALAPPDATA%\track on Windows,
+or $TRACK_HOME if set.
+
+Usage:
+  track migrate-data [newdir] [--dry-run]
+
+Examples:
+  track migrate-data
+  track migrate-data /opt/track --dry-run
+
+Notes:
+- newdir must be empty or not exist yet.
+- Moves across filesystems copy and then delete.
+- If a move fails, entries already moved are put back and data_dir is unchanged.
+- config.json and track.lock are not moved; they live in the config directory.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		var newDir string
+		if len(args) == 1 {
+			newDir = args[0]
+		} else if newDir, err = paths.DataDir(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			moves, err := mgr.PlanDataMigration(newDir)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Dry run: would move %d entries and set data_dir to %s:\n", len(moves), newDir)
+			for _, mv := range moves {
+				fmt.Printf("  %s -> %s\n", mv.From, mv.To)
+			}
+			return
+		}
+		if err := mgr.MigrateData(newDir); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateDataCmd)
+	migrateDataCmd.Flags().Bool("dry-run", false, "Show what would be moved without changing anything")
+}
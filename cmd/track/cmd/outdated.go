@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hako/durafmt"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/manager"
+)
+
+// Exit codes of 'track outdated'.
+const (
+	exitUpdatesAvailable = 1
+	exitCheckFailed      = 2
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated [repo...]",
+	Short: "Check tracked repositories for newer releases without installing anything",
+	Long: `Queries the latest release of every tracked repository (or the given ones) in parallel and shows the installed version next to the latest one. Nothing is downloaded and the config is not changed.
+
+Usage:
+  track outdated
+  track outdated rg fd
+  track outdated --quiet
+
+Flags:
+  -a, --all     Also show repositories that are up-to-date
+  -q, --quiet   Print nothing; only set the exit code
+
+Exit codes:
+  0   everything is up-to-date
+  1   updates are available
+  2   no updates found, but some repositories could not be checked
+
+Examples:
+  track outdated || track update      # in a cron job
+  track outdated -q || echo "updates"   # in a shell prompt
+
+Notes:
+` + refsHelp,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(exitCheckFailed)
+		}
+
+		repos, err := resolveRepos(mgr.Cfg, args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(exitCheckFailed)
+		}
+		if len(repos) == 0 {
+			return
+		}
+
+		showAll, _ := cmd.Flags().GetBool("all")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Repository", "Current", "Latest", "Released"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		table.SetAutoWrapText(false)
+
+		var outdated, failed int
+		for _, status := range mgr.CheckUpdates(repos) {
+			current := status.Current
+			if current == "" {
+				current = "Not installed"
+			}
+			switch {
+			case status.Err != nil:
+				failed++
+				table.Append([]string{status.Repo, current, "error: " + status.Err.Error(), ""})
+			case status.Outdated():
+				outdated++
+				table.Append([]string{status.Repo, current, status.Latest, releaseAge(status.PublishedAt)})
+			case showAll:
+				table.Append([]string{status.Repo, current, status.Latest + " (up-to-date)", releaseAge(status.PublishedAt)})
+			}
+		}
+
+		if !quiet {
+			if table.NumLines() > 0 {
+				table.Render()
+			}
+			if outdated == 0 && failed == 0 {
+				fmt.Println("All repositories are up-to-date.")
+			} else if outdated > 0 {
+				fmt.Printf("%d of %d repositories have updates available.\n", outdated, len(repos))
+			}
+		}
+
+		switch {
+		case outdated > 0:
+			os.Exit(exitUpdatesAvailable)
+		case failed > 0:
+			os.Exit(exitCheckFailed)
+		}
+	},
+}
+
+func releaseAge(published time.Time) string {
+	if published.IsZero() {
+		return "-"
+	}
+	return durafmt.ParseShort(time.Since(published)).String() + " ago"
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	addTagFlag(outdatedCmd)
+	outdatedCmd.ValidArgsFunction = completeRepos
+	outdatedCmd.Flags().BoolP("all", "a", false, "Also show repositories that are up-to-date")
+	outdatedCmd.Flags().BoolP("quiet", "q", false, "Print nothing; only set the exit code")
+}
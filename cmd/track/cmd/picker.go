@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/olekukonko/tablewriter"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/gh"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/sys"
+)
+
+// enableAssetPicker lets mgr ask the user to choose an asset when automatic
+// matching fails or cannot tell several assets apart. It does nothing unless stdin is a terminal, so scripts
+// still fail with the matcher's error.
+func enableAssetPicker(mgr *manager.Manager) {
+	if !sys.IsTerminal(os.Stdin) {
+		return
+	}
+	mgr.PickAsset = func(repoCfg *config.Repo, release *github.RepositoryRelease, scored []gh.ScoredAsset, hidden int, matchErr error) *github.ReleaseAsset {
+		fmt.Printf("\nCould not pick an asset for %s %s automatically: %v\n", repoCfg.Path, release.GetTagName(), matchErr)
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"#", "Asset", "Size", "Score", "Matches"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		table.SetAutoWrapText(false)
+		for i, s := range scored {
+			table.Append([]string{
+				strconv.Itoa(i + 1),
+				s.Asset.GetName(),
+				formatBytes(int64(s.Asset.GetSize())),
+				strconv.Itoa(s.Score),
+				strings.Join(s.Notes, ", "),
+			})
+		}
+		table.Render()
+		if hidden > 0 {
+			fmt.Printf("%d more assets are hidden by exclusion rules (checksums, signatures, excluded_patterns, asset_exclude).\n", hidden)
+		}
+
+		line := prompt("Pick an asset number (empty to cancel): ")
+		if line == "" {
+			return nil
+		}
+		num, err := strconv.Atoi(line)
+		if err != nil || num < 1 || num > len(scored) {
+			fmt.Printf("Invalid selection '%s'.\n", line)
+			return nil
+		}
+		asset := scored[num-1].Asset
+
+		filter := manager.DeriveAssetFilter(asset.GetName(), release.GetTagName())
+		fmt.Printf("\nDerived asset_filter: %s\n", filter)
+		checks, err := mgr.CheckAssetFilter(repoCfg, filter, release, asset.GetName())
+		allOK := true
+		for _, c := range checks {
+			switch {
+			case c.OK():
+				fmt.Printf("  %-12s picks %s\n", c.Tag, c.Got)
+			case c.Got == "":
+				allOK = false
+				fmt.Printf("  %-12s picks nothing (expected %s)\n", c.Tag, c.Expected)
+			default:
+				allOK = false
+				fmt.Printf("  %-12s picks %s (expected %s)\n", c.Tag, c.Got, c.Expected)
+			}
+		}
+		if err != nil {
+			fmt.Printf("  Could not check earlier releases: %v\n", err)
+		}
+
+		question := "Save this asset_filter for future releases?"
+		if !allOK {
+			question = "The filter does not pick the same asset everywhere. Save it anyway?"
+		}
+		if confirm(question) {
+			repoCfg.AssetFilter = filter
+			if tracked, ok := mgr.Cfg.Repos[repoCfg.Path]; ok && tracked == repoCfg {
+				if err := mgr.Cfg.Save(); err != nil {
+					fmt.Printf("Error saving config: %v\n", err)
+				}
+			}
+			fmt.Printf("Saved asset_filter for %s.\n", repoCfg.Path)
+		} else {
+			fmt.Println("Using the asset this time only.")
+		}
+		return asset
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/user/track/internal/manager"
+)
+
+// printPlan shows what an install would do for --dry-run.
+func printPlan(plan *manager.Plan) {
+	if plan.UpToDate {
+		fmt.Printf("%s: up-to-date (version %s), nothing to do.\n", plan.Repo, plan.Version)
+		return
+	}
+	current := plan.Current
+	if current == "" {
+		current = "not installed"
+	}
+	fmt.Printf("%s: %s -> %s\n", plan.Repo, current, plan.Version)
+	if plan.Build {
+		fmt.Printf("  build from source: %s\n", plan.URL)
+	} else {
+		size := ""
+		if plan.Size > 0 {
+			size = " (" + formatBytes(plan.Size) + ")"
+		}
+		fmt.Printf("  asset:       %s%s\n", plan.Asset, size)
+		fmt.Printf("  download:    %s\n", plan.URL)
+	}
+	fmt.Printf("  install dir: %s\n", plan.VersionDir)
+	for _, link := range plan.Links {
+		action := "create"
+		if _, err := os.Lstat(link); err == nil {
+			action = "replace"
+		}
+		fmt.Printf("  %-7s link: %s -> %s\n", action, link, plan.Executable)
+	}
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// prompt prints question and returns the trimmed line typed in reply.
+func prompt(question string) string {
+	fmt.Print(question)
+	line, _ := stdinReader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+// confirm asks a yes/no question; anything but y or yes means no.
+func confirm(question string) bool {
+	switch strings.ToLower(prompt(question + " [y/N]: ")) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
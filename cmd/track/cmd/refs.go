@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/sys"
+)
+
+// refsHelp is the Notes entry of commands that take repository references.
+const refsHelp = `- A repository can be named by owner/repo, its install name, an alias
+  (see 'track config set --repo <repo> aliases rg,grep'), a unique prefix
+  of any of these, or a glob such as 'charmbracelet/*'. The numbers shown
+  by 'track list' are accepted only when typing in a terminal, since they
+  shift whenever a repository is added.
+- --tag narrows the repositories to those with a tag (--tag k8s), or
+  without one (--tag '!experimental').`
+
+// flagTags holds the --tag selectors.
+var flagTags []string
+
+// addTagFlag registers --tag on a command that selects repositories with
+// resolveRepos or resolveRepo. Commands that ignore the selection do not
+// get the flag, so 'track sync --tag k8s' is an error rather than a sync
+// of everything.
+func addTagFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&flagTags, "tag", nil, "Only act on repositories with this tag; '!tag' excludes a tag (repeatable)")
+	cmd.RegisterFlagCompletionFunc("tag", completeTags)
+}
+
+// resolveRepos maps command arguments to tracked repos; no arguments means
+// all of them. List numbers are accepted only when stdin is a terminal.
+// The result is narrowed to the repos --tag selects.
+func resolveRepos(cfg *config.Config, args []string) ([]string, error) {
+	sel, err := config.ParseTagSelector(flagTags)
+	if err != nil {
+		return nil, err
+	}
+	repos := cfg.SortedRepos()
+	if len(args) > 0 {
+		if repos, err = cfg.Resolve(args, sys.IsTerminal(os.Stdin)); err != nil {
+			return nil, err
+		}
+	}
+	repos = cfg.Select(repos, sel)
+	if len(repos) == 0 && !sel.Empty() {
+		return nil, fmt.Errorf("no repositories selected by --tag %s", sel)
+	}
+	return repos, nil
+}
+
+// completeTags completes the tags in use, for --tag and 'track tag'.
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	neg := strings.HasPrefix(toComplete, "!")
+	var out []string
+	for _, t := range cfg.Tags() {
+		if neg {
+			t = "!" + t
+		}
+		if strings.HasPrefix(t, toComplete) {
+			out = append(out, t)
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// resolveRepo maps one argument to exactly one tracked repo, which must
+// also match --tag if given.
+func resolveRepo(cfg *config.Config, arg string) (string, error) {
+	repoPath, err := cfg.ResolveOne(arg, sys.IsTerminal(os.Stdin))
+	if err != nil {
+		return "", err
+	}
+	sel, err := config.ParseTagSelector(flagTags)
+	if err != nil {
+		return "", err
+	}
+	if !sel.Matches(cfg.Repos[repoPath]) {
+		return "", fmt.Errorf("%s is not selected by --tag %s", repoPath, sel)
+	}
+	return repoPath, nil
+}
+
+// resolveAnyRepo is resolveRepo for commands that also work on untracked
+// repositories: an owner/repo that names no tracked repo is returned as is,
+// unless --tag is given, which no untracked repo can match.
+func resolveAnyRepo(cfg *config.Config, arg string) (string, error) {
+	if strings.Count(arg, "/") == 1 && !strings.ContainsAny(arg, "*?[") {
+		if _, err := cfg.ResolveOne(arg, false); err != nil {
+			if len(flagTags) > 0 {
+				return "", fmt.Errorf("%s is not tracked, so --tag cannot select it", arg)
+			}
+			return arg, nil
+		}
+	}
+	return resolveRepo(cfg, arg)
+}
+
+// completeRepos completes tracked repos by owner/repo and their names.
+func completeRepos(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Get()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	for _, k := range cfg.SortedRepos() {
+		for _, name := range append([]string{k}, cfg.RepoNames(k)...) {
+			if strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+				out = append(out, name)
+			}
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRepo completes only the first argument, for commands like
+// 'track rollback <repo> <tag>'.
+func completeRepo(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeRepos(cmd, args, toComplete)
+}
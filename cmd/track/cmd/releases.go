@@ -10,28 +10,29 @@ import (
 	"github.com/hako/durafmt"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
 	"github.com/user/track/internal/gh"
 	"github.com/user/track/internal/manager"
 )
 
 var releasesCmd = &cobra.Command{
-	Use:   "releases <number>",
+	Use:   "releases <repo>",
 	Short: "Show version history and recent releases for a repository",
 	Long: `Shows the installed version history and recent releases from GitHub for a tracked repository.
 
 Usage:
-  track releases <number>
-  track releases <number> --limit 5
+  track releases <repo>
+  track releases <repo> --limit 5
 
 Flags:
   -l, --limit   Number of recent releases to show (default 10)
 
 Examples:
-  track releases 1
-  track releases 2 --limit 5
+  track releases BurntSushi/ripgrep
+  track releases rg --limit 5
 
 Notes:
-- The number refers to the index in 'track list'.
+` + refsHelp + `
 - Shows both installed versions and recent GitHub releases.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -41,9 +42,9 @@ Notes:
 			return
 		}
 
-		repoPath := getReposFromArgs([]string{args[0]}, mgr.Cfg)[0]
-		if repoPath == "" {
-			fmt.Println("Invalid repository number provided.")
+		repoPath, err := resolveRepo(mgr.Cfg, args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
 			return
 		}
 
@@ -56,6 +57,11 @@ Notes:
 		}
 		fmt.Println()
 
+		if repoCfg.Source == config.SourceURL || repoCfg.Source == config.SourceTags {
+			fmt.Println("Release listing is only available for GitHub sources.")
+			return
+		}
+
 		limit, _ := cmd.Flags().GetInt("limit")
 		client := gh.NewClient(context.Background(), "")
 		releases, err := client.ListReleases(context.Background(), owner, name, limit)
@@ -88,5 +94,7 @@ Notes:
 
 func init() {
 	rootCmd.AddCommand(releasesCmd)
+	addTagFlag(releasesCmd)
+	releasesCmd.ValidArgsFunction = completeRepo
 	releasesCmd.Flags().IntP("limit", "l", 10, "Number of recent releases to show from GitHub")
 }
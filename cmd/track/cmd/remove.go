@@ -2,33 +2,39 @@ package cmd
 
 import (
 	"fmt"
-	"sort"
-	"strconv"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/sys"
 )
 
 var removeCmd = &cobra.Command{
-	Use:   "remove <number>",
+	Use:   "remove <repo...>",
 	Short: "Remove a repository from tracking",
-	Long: `Removes a repository from the tracked list by its number as shown in 'track list'.
+	Long: `Removes one or more repositories from the tracked list.
 
 Usage:
-  track remove <number>
+  track remove <repo...>
 
 Aliases:
   rm
 
 Examples:
-  track remove 1
-  track rm 2
+  track remove BurntSushi/ripgrep
+  track rm fd
+  track rm 'charmbracelet/*'
 
 Notes:
-- The number refers to the index in the 'track list' table.
+` + refsHelp + `
+- When a reference matches several repositories, or matches only as a
+  prefix, you are asked to confirm in a terminal. Without a terminal, a
+  prefix is refused: name the repository exactly.
 - This does not delete downloaded binaries or data folders (see 'track tidy' to clean up).`,
-	Aliases: []string{"rm"},
-	Args:    cobra.ExactArgs(1),
+	Aliases:           []string{"rm"},
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeRepos,
 	Run: func(cmd *cobra.Command, args []string) {
 		cfg, err := config.Get()
 		if err != nil {
@@ -36,26 +42,37 @@ Notes:
 			return
 		}
 
-		num, err := strconv.Atoi(args[0])
+		repos, err := resolveRepos(cfg, args)
 		if err != nil {
-			fmt.Printf("Error: Invalid number provided '%s'.\n", args[0])
-			return
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
 		}
 
-		keys := make([]string, 0, len(cfg.Repos))
-		for k := range cfg.Repos {
-			keys = append(keys, k)
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			for _, repoPath := range repos {
+				fmt.Printf("Dry run: would remove '%s' from tracking. Installed files and links would be kept.\n", repoPath)
+			}
+			return
 		}
-		sort.Strings(keys)
-
-		if num < 1 || num > len(keys) {
-			fmt.Printf("Error: Number %d is out of bounds.\n", num)
+		var prefixes []string
+		for _, arg := range args {
+			if cfg.ByPrefix(arg, sys.IsTerminal(os.Stdin)) {
+				prefixes = append(prefixes, arg)
+			}
+		}
+		if len(prefixes) > 0 && !sys.IsTerminal(os.Stdin) {
+			fmt.Printf("Error: %s matched by prefix only; name the repositories exactly (owner/repo, name or alias) to remove them\n", strings.Join(prefixes, ", "))
+			os.Exit(1)
+		}
+		if (len(repos) > len(args) || len(prefixes) > 0) && sys.IsTerminal(os.Stdin) &&
+			!confirm(fmt.Sprintf("Remove %d repositories (%s)?", len(repos), strings.Join(repos, ", "))) {
+			fmt.Println("Cancelled; nothing was removed.")
 			return
 		}
-
-		repoToRemove := keys[num-1]
-		fmt.Printf("Removing '%s' from tracking.\n", repoToRemove)
-		delete(cfg.Repos, repoToRemove)
+		for _, repoPath := range repos {
+			fmt.Printf("Removing '%s' from tracking.\n", repoPath)
+			delete(cfg.Repos, repoPath)
+		}
 
 		if err := cfg.Save(); err != nil {
 			fmt.Printf("Error saving configuration: %v\n", err)
@@ -67,4 +84,6 @@ Notes:
 
 func init() {
 	rootCmd.AddCommand(removeCmd)
+	addTagFlag(removeCmd)
+	removeCmd.Flags().Bool("dry-run", false, "Show what would be removed without changing anything")
 }
@@ -8,18 +8,18 @@ import (
 )
 
 var rollbackCmd = &cobra.Command{
-	Use:   "rollback <number> <version_tag>",
+	Use:   "rollback <repo> <version_tag>",
 	Short: "Roll back a repository to a specific version",
 	Long: `Downloads and installs a specific, older version of a repository.
 
 Usage:
-  track rollback <number> <version_tag>
+  track rollback <repo> <version_tag>
 
 Examples:
-  track rollback 1 v1.2.3
+  track rollback BurntSushi/ripgrep 14.0.0
 
 Notes:
-- The number refers to the index in 'track list'.
+` + refsHelp + `
 - The version_tag must be a valid release tag from the repository.
 - This command is not implemented in this version and will print a message.`,
 	Args: cobra.ExactArgs(2),
@@ -30,9 +30,8 @@ Notes:
 			return
 		}
 
-		reposToUpdate := getReposFromArgs([]string{args[0]}, mgr.Cfg)
-		if len(reposToUpdate) != 1 {
-			fmt.Println("Invalid repository number provided.")
+		if _, err := resolveRepo(mgr.Cfg, args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
 			return
 		}
 
@@ -42,4 +41,6 @@ Notes:
 
 func init() {
 	rootCmd.AddCommand(rollbackCmd)
+	addTagFlag(rollbackCmd)
+	rollbackCmd.ValidArgsFunction = completeRepo
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/gh"
+	"github.com/user/track/internal/manager"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search GitHub for repositories to track",
+	Long: `Searches GitHub repositories (sorted by stars) and shows each result's latest release and whether it has an asset for this platform.
+
+Usage:
+  track search <query>
+  track search <query> --limit 5
+  track search <query> --add
+
+Flags:
+  -l, --limit   Number of results to show (default 10)
+  --add         Pick a result interactively and start tracking it
+
+Examples:
+  track search ripgrep
+  track search "fuzzy finder" --add`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		ctx := context.Background()
+		client := gh.NewClient(ctx, "")
+		result, err := client.SearchRepos(ctx, strings.Join(args, " "), limit)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(result.Repositories) == 0 {
+			fmt.Println("No repositories found.")
+			return
+		}
+
+		repos := result.Repositories
+		if len(repos) > limit {
+			repos = repos[:limit]
+		}
+
+		// Release lookups are independent, so run them in parallel.
+		latest := make([]string, len(repos))
+		matches := make([]string, len(repos))
+		var wg sync.WaitGroup
+		for i, repo := range repos {
+			wg.Add(1)
+			go func(i int, repo *github.Repository) {
+				defer wg.Done()
+				latest[i], matches[i] = releaseSummary(ctx, client, repo, &mgr.Cfg.Global)
+			}(i, repo)
+		}
+		wg.Wait()
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"#", "Repository", "Stars", "Latest", "Asset", "Description"})
+		table.SetAutoWrapText(false)
+		for i, repo := range repos {
+			table.Append([]string{
+				strconv.Itoa(i + 1),
+				repo.GetFullName(),
+				strconv.Itoa(repo.GetStargazersCount()),
+				latest[i],
+				matches[i],
+				truncate(repo.GetDescription(), 60),
+			})
+		}
+		table.Render()
+
+		if add, _ := cmd.Flags().GetBool("add"); !add {
+			return
+		}
+
+		line := prompt("Enter a number to track (empty to cancel): ")
+		if line == "" {
+			return
+		}
+		num, err := strconv.Atoi(line)
+		if err != nil || num < 1 || num > len(repos) {
+			fmt.Printf("Error: Invalid selection '%s'.\n", line)
+			return
+		}
+
+		repoPath := repos[num-1].GetFullName()
+		newRepo := &config.Repo{IncludePrerelease: mgr.Cfg.Global.DefaultPrerelease}
+		if err := mgr.AddAndInstall(repoPath, newRepo, nil); err != nil {
+			fmt.Printf("Error during initial install: %v\n", err)
+		}
+	},
+}
+
+// releaseSummary returns the latest stable tag of repo and whether any of its
+// assets fits this platform.
+func releaseSummary(ctx context.Context, client *gh.Client, repo *github.Repository, global *config.GlobalConfig) (string, string) {
+	release, err := client.GetLatestRelease(ctx, repo.GetOwner().GetLogin(), repo.GetName(), false)
+	if err != nil {
+		return "-", "-"
+	}
+	if _, err := gh.FindCompatibleAsset(release, &config.Repo{}, global); err != nil {
+		return release.GetTagName(), "no"
+	}
+	return release.GetTagName(), "yes"
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().IntP("limit", "l", 10, "Number of results to show")
+	searchCmd.Flags().Bool("add", false, "Pick a result interactively and start tracking it")
+}
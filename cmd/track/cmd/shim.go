@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/manager"
+)
+
+// shimCmd is what the shim scripts in shim mode call. It is not meant to be
+// run by hand.
+var shimCmd = &cobra.Command{
+	Use:                "shim <name> [args...]",
+	Short:              "Run a tool using the version of the nearest project manifest",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Args:               cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		config.Notices = io.Discard
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "track: %v\n", err)
+			os.Exit(1)
+		}
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "track: %v\n", err)
+			os.Exit(1)
+		}
+		exe, err := mgr.ResolveShim(args[0], cwd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "track: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(runBinary(exe, args[1:]))
+	},
+}
+
+// runBinary runs path with args attached to this terminal and returns its
+// exit code.
+func runBinary(path string, args []string) int {
+	c := exec.Command(path, args...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(os.Stderr, "track: %v\n", err)
+		return 127
+	}
+	return 0
+}
+
+// progressToStderr runs fn with os.Stdout pointing at stderr, so that what
+// track prints while fetching a tool does not mix with the tool's output.
+func progressToStderr(fn func()) {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+	fn()
+}
+
+func init() {
+	rootCmd.AddCommand(shimCmd)
+}
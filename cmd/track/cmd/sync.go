@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/lockfile"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/portable"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Install exactly the versions recorded in track.lock",
+	Long: `Installs the exact tag and asset recorded in the lockfile for this platform, verifying each download against its sha256 digest. Repositories in the lockfile that are not tracked yet are added with the settings recorded by 'track lock'.
+
+Usage:
+  track sync
+  track sync --file ./track.lock
+
+Flags:
+  --file   Lockfile path (default: track.lock next to config.json)
+
+Notes:
+- Tools already at the locked version with a matching digest are left alone.
+- A digest mismatch aborts the install of that tool and leaves the current version in place.
+- Exits with status 1 if any tool could not be synced.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		path, err := lockfilePath(cmd)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if _, err := os.Stat(path); err != nil {
+			fmt.Printf("Error: lockfile %s not found. Run 'track lock' first.\n", path)
+			os.Exit(1)
+		}
+		lock, err := lockfile.Load(path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		keys := make([]string, 0, len(lock.Repos))
+		for k := range lock.Repos {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var failed int
+		for _, repoPath := range keys {
+			entry := lock.Repos[repoPath]
+			if _, ok := mgr.Cfg.Repos[repoPath]; !ok {
+				if err := addLocked(mgr, repoPath, entry); err != nil {
+					fmt.Printf("Failed to sync %s: %v\n", repoPath, err)
+					failed++
+				}
+				continue
+			}
+			changed, err := mgr.SyncLocked(repoPath, entry)
+			switch {
+			case err != nil:
+				fmt.Printf("Failed to sync %s: %v\n", repoPath, err)
+				failed++
+			case !changed:
+				fmt.Printf("%s is in sync (version %s).\n", repoPath, entry.Tag)
+			}
+		}
+		if failed > 0 {
+			fmt.Printf("%d of %d tools could not be synced.\n", failed, len(keys))
+			os.Exit(1)
+		}
+	},
+}
+
+// addLocked tracks a repo with the settings recorded in its lockfile entry
+// and installs the locked asset; if that fails, the repo is not tracked.
+// Entries written before settings were recorded are refused: adding them as
+// plain GitHub repos would lose their source, filters and build settings.
+func addLocked(mgr *manager.Manager, repoPath string, entry *lockfile.Entry) error {
+	if len(entry.Repo) == 0 {
+		return fmt.Errorf("not tracked, and the lockfile does not record its settings; add it with 'track add' or re-run 'track lock' where it is tracked")
+	}
+	repo, err := portable.ParseRepoSettings(entry.Repo)
+	if err != nil {
+		return fmt.Errorf("invalid repo settings in lockfile: %w", err)
+	}
+	if err := validateNewRepo(mgr.Cfg, repoPath, repo); err != nil {
+		return err
+	}
+	return mgr.AddAndSync(repoPath, repo, entry)
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().String("file", "", "Lockfile path (default: track.lock next to config.json)")
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/config"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Group tracked repositories with tags",
+	Long: `Tags group tracked repositories by purpose, e.g. k8s, git, shell or work.
+Every command that acts on several repositories takes --tag to select them.
+
+Usage:
+  track tag add <tag> <repo...>
+  track tag rm <tag> <repo...>
+  track tag list
+
+Examples:
+  track tag add k8s kubectl helm k9s
+  track tag add experimental 'charmbracelet/*'
+  track tag rm work --tag k8s        # untag every k8s repo
+  track update --tag k8s
+  track update --tag '!experimental'
+  track list --tag work
+
+Notes:
+- Tags are lower-case single words; they are stored in each repo's "tags" list.
+- Repeated --tag flags select repos with any of the tags; '!tag' excludes
+  repos with that tag. --tag k8s --tag '!experimental' selects k8s repos
+  that are not experimental.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:               "add <tag> <repo...>",
+	Short:             "Tag repositories",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTagArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		changeTags(args, func(repo *config.Repo, tag string) (bool, error) {
+			return repo.AddTag(tag)
+		}, "Tagged")
+	},
+}
+
+var tagRmCmd = &cobra.Command{
+	Use:               "rm <tag> <repo...>",
+	Aliases:           []string{"remove"},
+	Short:             "Remove a tag from repositories",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeTagArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		changeTags(args, func(repo *config.Repo, tag string) (bool, error) {
+			return repo.RemoveTag(tag), nil
+		}, "Untagged")
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List tags and how many repositories have each",
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Get()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		tags := cfg.Tags()
+		if len(tags) == 0 {
+			fmt.Println("No tags yet. Use 'track tag add <tag> <repo...>' to add one.")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Tag", "Repos", "Repositories"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+		table.SetCenterSeparator("|")
+		table.SetAutoWrapText(false)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+		for _, tag := range tags {
+			repos := cfg.Select(cfg.SortedRepos(), config.TagSelector{Include: []string{tag}})
+			table.Append([]string{tag, strconv.Itoa(len(repos)), strings.Join(repos, ", ")})
+		}
+		table.Render()
+	},
+}
+
+// changeTags applies change to the tag args[0] of the repos named by the
+// remaining args or selected by --tag, and saves the config.
+func changeTags(args []string, change func(*config.Repo, string) (bool, error), verb string) {
+	cfg, err := config.Get()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	tag, refs := args[0], args[1:]
+	if len(refs) == 0 && len(flagTags) == 0 {
+		fmt.Println("Error: name the repositories to change, or select them with --tag.")
+		os.Exit(1)
+	}
+	repos, err := resolveRepos(cfg, refs)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	changed := 0
+	for _, repoPath := range repos {
+		ok, err := change(cfg.Repos[repoPath], tag)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if ok {
+			changed++
+			fmt.Printf("%s %s: %s\n", verb, repoPath, config.NormalizeTag(tag))
+		}
+	}
+	if changed == 0 {
+		fmt.Println("Nothing to change.")
+		return
+	}
+	if err := cfg.Save(); err != nil {
+		fmt.Printf("Error saving configuration: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// completeTagArgs completes the tag first, then repositories.
+func completeTagArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeTags(cmd, args, toComplete)
+	}
+	return completeRepos(cmd, args, toComplete)
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd, tagRmCmd, tagListCmd)
+	addTagFlag(tagAddCmd)
+	addTagFlag(tagRmCmd)
+	rootCmd.AddCommand(tagCmd)
+}
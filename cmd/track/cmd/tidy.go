@@ -3,58 +3,126 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
-	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/manager"
+	"github.com/user/track/internal/sys"
 )
 
 var tidyCmd = &cobra.Command{
-	Use:   "tidy",
+	Use:   "tidy [repo...]",
 	Short: "Delete all previous version folders for all tracked repositories (keep only current)",
-	Long: `Deletes all version folders except the currently active one for each tracked repository.
+	Long: `Deletes all version folders except the currently active one for each tracked repository, or only the ones named.
 
 Usage:
-  track tidy
+  track tidy [repo...]
+  track tidy --dry-run
+  track tidy [--keep N] [--older-than 30d] [--orphans] [--interactive]
 
 Examples:
   track tidy
+  track tidy rg fd
+  track tidy --tag k8s
+  track tidy --keep 1                  # keep the previous version for rollback
+  track tidy --older-than 30d
+  track tidy --orphans --dry-run
+  track tidy --interactive
+
+Flags:
+  --keep N           Keep the N newest versions besides the current one
+  --older-than age   Only delete versions installed longer ago than age (e.g. 30d, 2w, 12h)
+  --orphans          Also delete folders of repositories that are no longer tracked
+  --interactive, -i  Ask before deleting each folder
+  --dry-run          List what would be deleted without deleting anything
 
 Notes:
 - This command helps free up disk space by removing old versions.
-- Only the currently installed version for each repo is kept.`,
+- Only the currently installed version for each repo is kept, plus what
+  --keep and --older-than spare. Repos with no current version are left
+  alone.
+- Versions pinned by track.lock or by the .track.json/.track.toml of the
+  current directory (or its parents) are kept. Manifests of other projects
+  are not seen: run 'track install' there to download what tidy removed.
+- 'track remove' keeps installed files; --orphans cleans them up. Orphans
+  are found across all of data_dir, whatever repos are named.
+- --dry-run lists the folders that would be deleted and the space that would be freed.
+- Use 'track du' to see what takes space first.
+` + refsHelp,
 	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.Get()
+		mgr, err := manager.New()
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 			return
 		}
-		for repoKey, repo := range cfg.Repos {
-			if repo.CurrentVersion == "" {
+		repos, err := resolveRepos(mgr.Cfg, args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		orphans, _ := cmd.Flags().GetBool("orphans")
+		var opts manager.TidyOptions
+		opts.Keep, _ = cmd.Flags().GetInt("keep")
+		if opts.Keep < 0 {
+			fmt.Println("Error: --keep must not be negative")
+			os.Exit(1)
+		}
+		if age, _ := cmd.Flags().GetString("older-than"); age != "" {
+			if opts.OlderThan, err = manager.ParseAge(age); err != nil {
+				fmt.Printf("Error: --older-than: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if wd, err := os.Getwd(); err == nil {
+			opts.Pinned = manager.PinnedVersions(wd)
+		}
+		if interactive && !dryRun && !sys.IsTerminal(os.Stdin) {
+			fmt.Println("Error: --interactive needs a terminal")
+			os.Exit(1)
+		}
+
+		candidates := mgr.TidyCandidates(repos, opts)
+		if orphans {
+			candidates = append(candidates, mgr.Orphans()...)
+		}
+
+		var freed int64
+		for _, dir := range candidates {
+			what := "old version"
+			if dir.Version == "" {
+				what = "orphaned folder"
+			}
+			if dryRun {
+				fmt.Printf("Would delete %s (%s)\n", dir.Path, formatBytes(dir.Bytes))
+				freed += dir.Bytes
 				continue
 			}
-			_, name, _ := strings.Cut(repoKey, "/")
-			repoDir := filepath.Join(cfg.Global.DataDir, name, "general")
-			entries, err := os.ReadDir(repoDir)
-			if err != nil {
+			if interactive && !confirm(fmt.Sprintf("Delete %s %s (%s)?", what, dir.Path, formatBytes(dir.Bytes))) {
 				continue
 			}
-			for _, entry := range entries {
-				if !entry.IsDir() {
-					continue
-				}
-				if entry.Name() != repo.CurrentVersion {
-					path := filepath.Join(repoDir, entry.Name())
-					os.RemoveAll(path)
-					fmt.Printf("Deleted old version: %s\n", path)
-				}
+			if err := mgr.RemoveVersion(dir); err != nil {
+				fmt.Printf("Failed to delete %s: %v\n", dir.Path, err)
+				continue
 			}
+			fmt.Printf("Deleted %s: %s (%s)\n", what, dir.Path, formatBytes(dir.Bytes))
+			freed += dir.Bytes
+		}
+		if dryRun {
+			fmt.Printf("Dry run: %s would be freed.\n", formatBytes(freed))
+			return
 		}
-		fmt.Println("Tidy complete.")
+		fmt.Printf("Tidy complete. Freed %s.\n", formatBytes(freed))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(tidyCmd)
+	addTagFlag(tidyCmd)
+	tidyCmd.ValidArgsFunction = completeRepos
+	tidyCmd.Flags().Bool("dry-run", false, "List what would be deleted without deleting anything")
+	tidyCmd.Flags().Int("keep", 0, "Keep the N newest versions besides the current one")
+	tidyCmd.Flags().String("older-than", "", "Only delete versions installed longer ago than this, e.g. 30d or 2w")
+	tidyCmd.Flags().Bool("orphans", false, "Also delete folders of repositories that are no longer tracked")
+	tidyCmd.Flags().BoolP("interactive", "i", false, "Ask before deleting each folder")
 }
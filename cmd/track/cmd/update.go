@@ -2,34 +2,35 @@ package cmd
 
 import (
 	"fmt"
-	"sort"
-	"strconv"
+	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/user/track/internal/config"
 	"github.com/user/track/internal/manager"
 	"github.com/user/track/internal/updater"
 )
 
 var updateCmd = &cobra.Command{
-	Use:   "update [number]",
+	Use:   "update [repo...]",
 	Short: "Update tracked repositories to their latest versions (and track itself)",
-	Long: `Checks for and installs new releases for all tracked repositories, or a specific one if a number is provided.
+	Long: `Checks for and installs new releases for all tracked repositories, or only the ones named.
 
 Usage:
-  track update           # Update all tracked repositories and the track CLI itself
-  track update 2         # Update only the repository at position 2
-  track update --force   # Force update even if versions match
+  track update                      # Update all tracked repositories and the track CLI itself
+  track update BurntSushi/ripgrep   # Update only ripgrep
+  track update --force              # Force update even if versions match
+  track update --dry-run            # Show what would be installed and linked
 
 Examples:
   track update
-  track update 1
+  track update rg fd
+  track update 'charmbracelet/*'
   track update --force
 
 Notes:
-- The number refers to the index shown in 'track list'.
+` + refsHelp + `
 - After updating repositories, the track CLI will check for its own updates.
-- The --force/-f flag forces an update even if the current version matches the latest.`,
+- The --force/-f flag forces an update even if the current version matches the latest.
+- --dry-run resolves releases, assets, install paths and links, prints the plan and changes nothing (the self-update check is skipped).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		mgr, err := manager.New()
 		if err != nil {
@@ -44,14 +45,28 @@ Notes:
 			return
 		}
 
-		reposToUpdate := getReposFromArgs(args, mgr.Cfg)
-		if len(reposToUpdate) == 0 && len(args) > 0 {
-			fmt.Println("Invalid repository number provided.")
-			return
+		reposToUpdate, err := resolveRepos(mgr.Cfg, args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
 		}
 
 		forceUpdate, _ := cmd.Flags().GetBool("force")
 
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			fmt.Println("Dry run: nothing will be downloaded, linked or saved.")
+			for _, repoPath := range reposToUpdate {
+				plan, err := mgr.PlanUpdate(repoPath, forceUpdate)
+				if err != nil {
+					fmt.Printf("%s: %v\n", repoPath, err)
+					continue
+				}
+				printPlan(plan)
+			}
+			return
+		}
+
+		enableAssetPicker(mgr)
 		for _, repoPath := range reposToUpdate {
 			if err := mgr.UpdateRepo(repoPath, forceUpdate); err != nil {
 				fmt.Printf("Failed to update %s: %v\n", repoPath, err)
@@ -73,30 +88,10 @@ func checkSelfUpdate() {
 	}
 }
 
-func getReposFromArgs(args []string, cfg *config.Config) []string {
-	keys := make([]string, 0, len(cfg.Repos))
-	for k := range cfg.Repos {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-
-	if len(args) == 0 {
-		return keys
-	}
-
-	var repos []string
-	for _, arg := range args {
-		num, err := strconv.Atoi(arg)
-		if err != nil || num < 1 || num > len(keys) {
-			fmt.Printf("Warning: Invalid repository number '%s', skipping.\n", arg)
-			continue
-		}
-		repos = append(repos, keys[num-1])
-	}
-	return repos
-}
-
 func init() {
 	rootCmd.AddCommand(updateCmd)
+	addTagFlag(updateCmd)
+	updateCmd.ValidArgsFunction = completeRepos
 	updateCmd.Flags().BoolP("force", "f", false, "Force update even if versions match")
+	updateCmd.Flags().Bool("dry-run", false, "Print the update plan without changing anything")
 }
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/user/track/internal/manager"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [repo...]",
+	Short: "Check installed files against the manifest recorded at install time",
+	Long: `Every install writes .track-manifest.json into the version folder, listing
+each file with its size, mode and sha256 and the sha256 of the asset it came
+from. verify re-reads the files and reports any that were changed, removed or
+added since, e.g. by tampering or disk corruption. The downloaded asset, if
+still in the folder, is checked against the sha256 taken at download time.
+
+The sha256 of each manifest is also recorded in the config directory
+(manifests/<owner>/<repo>/<tag>.sha256, readable only by you), so rewriting
+a manifest inside data_dir to match tampered files is detected too.
+
+Usage:
+  track verify [repo...] [--all] [--strict]
+
+Examples:
+  track verify
+  track verify rg fd
+  track verify --all --strict
+
+Flags:
+  --all      Verify every installed version, not only the current one
+  --strict   Treat versions without a manifest as failures
+
+Notes:
+- Versions installed before track recorded manifests have none; reinstall
+  them (e.g. delete the folder and run 'track doctor --fix') to create one.
+- Versions installed before track recorded manifest digests are checked,
+  but reported as unverified; --strict fails them. Reinstall them with
+  'track update <repo> --force' to record a digest.
+- Exits with status 1 if any file does not match its manifest.
+` + refsHelp,
+	ValidArgsFunction: completeRepos,
+	Run: func(cmd *cobra.Command, args []string) {
+		mgr, err := manager.New()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		repos, err := resolveRepos(mgr.Cfg, args)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		all, _ := cmd.Flags().GetBool("all")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		checked, failed, unverified := 0, 0, 0
+		for _, repoPath := range repos {
+			versions := mgr.InstalledVersions(repoPath)
+			if !all {
+				versions = nil
+				if v := mgr.Cfg.Repos[repoPath].CurrentVersion; v != "" {
+					versions = []string{v}
+				}
+			}
+			for _, version := range versions {
+				checked++
+				mf, problems, err := mgr.Verify(repoPath, version)
+				switch {
+				case os.IsNotExist(err):
+					fmt.Printf("%s %s: no manifest, cannot verify\n", repoPath, version)
+					unverified++
+				case errors.Is(err, manager.ErrNoDigest) && len(problems) > 0:
+					fmt.Printf("%s %s: %d files do not match the manifest, which is unrecorded\n", repoPath, version, len(problems))
+					for _, p := range problems {
+						fmt.Printf("  %s: %s\n", p.Path, p.Message)
+					}
+					failed++
+				case errors.Is(err, manager.ErrNoDigest):
+					fmt.Printf("%s %s: files match (%d), but %v\n", repoPath, version, len(mf.Files), err)
+					unverified++
+				case err != nil:
+					fmt.Printf("%s %s: %v\n", repoPath, version, err)
+					failed++
+				case len(problems) > 0:
+					fmt.Printf("%s %s: %d files do not match the manifest of %s\n", repoPath, version, len(problems), mf.Created.Local().Format("2006-01-02 15:04"))
+					for _, p := range problems {
+						fmt.Printf("  %s: %s\n", p.Path, p.Message)
+					}
+					failed++
+				default:
+					fmt.Printf("%s %s: ok (%d files)\n", repoPath, version, len(mf.Files))
+				}
+			}
+		}
+		if checked == 0 {
+			fmt.Println("Nothing is installed.")
+			return
+		}
+		if strict {
+			failed += unverified
+		}
+		if failed > 0 {
+			fmt.Printf("%d of %d versions failed verification.\n", failed, checked)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	addTagFlag(verifyCmd)
+	verifyCmd.Flags().Bool("all", false, "Verify every installed version, not only the current one")
+	verifyCmd.Flags().Bool("strict", false, "Fail for versions that have no manifest")
+}
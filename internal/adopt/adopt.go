@@ -0,0 +1,248 @@
+// Package adopt finds tools that were installed by hand on PATH and works
+// out which GitHub repository and version they came from.
+package adopt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Known maps binary names to the repositories that publish them.
+var Known = map[string]string{
+	"age":           "FiloSottile/age",
+	"atuin":         "atuinsh/atuin",
+	"bat":           "sharkdp/bat",
+	"btm":           "ClementTsang/bottom",
+	"delta":         "dandavison/delta",
+	"direnv":        "direnv/direnv",
+	"dust":          "bootandy/dust",
+	"eza":           "eza-community/eza",
+	"fd":            "sharkdp/fd",
+	"fzf":           "junegunn/fzf",
+	"gdu":           "dundee/gdu",
+	"gh":            "cli/cli",
+	"glow":          "charmbracelet/glow",
+	"golangci-lint": "golangci/golangci-lint",
+	"gum":           "charmbracelet/gum",
+	"helm":          "helm/helm",
+	"hx":            "helix-editor/helix",
+	"hyperfine":     "sharkdp/hyperfine",
+	"jq":            "jqlang/jq",
+	"just":          "casey/just",
+	"k3d":           "k3d-io/k3d",
+	"k9s":           "derailed/k9s",
+	"kind":          "kubernetes-sigs/kind",
+	"lazydocker":    "jesseduffield/lazydocker",
+	"lazygit":       "jesseduffield/lazygit",
+	"lsd":           "lsd-rs/lsd",
+	"micro":         "zyedidia/micro",
+	"nvim":          "neovim/neovim",
+	"procs":         "dalance/procs",
+	"rg":            "BurntSushi/ripgrep",
+	"sd":            "chmln/sd",
+	"shellcheck":    "koalaman/shellcheck",
+	"shfmt":         "mvdan/sh",
+	"sops":          "getsops/sops",
+	"starship":      "starship/starship",
+	"tokei":         "XAMPPRocky/tokei",
+	"xh":            "ducaale/xh",
+	"yq":            "mikefarah/yq",
+	"zoxide":        "ajeetdsouza/zoxide",
+}
+
+// Candidate is an unmanaged binary that maps to a repository.
+type Candidate struct {
+	Name     string   // binary name, e.g. rg
+	Path     string   // first match on PATH, the one the shell runs
+	Repo     string   // owner/repo
+	Version  string   // parsed from --version output, "" if unknown
+	Shadowed []string // later PATH entries with the same name
+}
+
+// Scan looks for the binaries in mapping in dirs, in order. Files that
+// resolve into skipDir (track's data directory) are managed already and
+// ignored. Versions are not detected yet; see DetectVersion.
+func Scan(dirs []string, mapping map[string]string, skipDir string) []Candidate {
+	byName := make(map[string]*Candidate)
+	var names []string
+	seenDir := make(map[string]bool)
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		// /bin is often a link to /usr/bin; scan each real directory once.
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err != nil || seenDir[resolved] {
+			continue
+		}
+		seenDir[resolved] = true
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := binaryName(entry.Name())
+			repo, ok := mapping[name]
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) || isWithin(path, skipDir) {
+				continue
+			}
+			if c, ok := byName[name]; ok {
+				c.Shadowed = append(c.Shadowed, path)
+				continue
+			}
+			byName[name] = &Candidate{Name: name, Path: path, Repo: repo}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	candidates := make([]Candidate, 0, len(names))
+	for _, n := range names {
+		candidates = append(candidates, *byName[n])
+	}
+	return candidates
+}
+
+// binaryName strips the executable extension on Windows.
+func binaryName(file string) string {
+	if runtime.GOOS == "windows" {
+		return strings.TrimSuffix(strings.ToLower(file), ".exe")
+	}
+	return file
+}
+
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || fi.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+	return fi.Mode()&0111 != 0
+}
+
+// isWithin reports whether path, with symlinks resolved, is inside dir.
+func isWithin(path, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		resolved = path
+	}
+	if d, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = d
+	}
+	rel, err := filepath.Rel(dir, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// versionPattern finds the first version number in --version output.
+var versionPattern = regexp.MustCompile(`\bv?(\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.]+)?)\b`)
+
+// DetectVersion runs path with --version, then -V, and returns the first
+// version number printed, e.g. "14.1.0" for "ripgrep 14.1.0 (rev e50df40a19)".
+func DetectVersion(path string, timeout time.Duration) (string, error) {
+	var lastErr error
+	for _, flag := range []string{"--version", "-V"} {
+		out, err := run(path, flag, timeout)
+		if v := ParseVersion(out); v != "" {
+			return v, nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return "", fmt.Errorf("%s --version: %w", filepath.Base(path), lastErr)
+	}
+	return "", fmt.Errorf("%s --version printed no version number", filepath.Base(path))
+}
+
+// ParseVersion returns the first version number in the first lines of
+// --version output, or "".
+func ParseVersion(out string) string {
+	if m := versionPattern.FindStringSubmatch(firstLines(out, 3)); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func run(path, flag string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, flag)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	return out.String(), err
+}
+
+// firstLines keeps the start of the output, where tools print their own
+// version before e.g. the versions of libraries they link.
+func firstLines(s string, n int) string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() && len(lines) < n {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseMapping reads a user mapping: one "name owner/repo" or
+// "name=owner/repo" per line, # starting a comment.
+func ParseMapping(data []byte) (map[string]string, error) {
+	mapping := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, repo, err := ParseMapEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		mapping[name] = repo
+	}
+	return mapping, scanner.Err()
+}
+
+// ParseMapEntry parses "name=owner/repo" or "name owner/repo".
+func ParseMapEntry(s string) (name, repo string, err error) {
+	name, repo, ok := strings.Cut(s, "=")
+	if !ok {
+		fields := strings.Fields(s)
+		if len(fields) == 2 {
+			name, repo, ok = fields[0], fields[1], true
+		}
+	}
+	name, repo = strings.TrimSpace(name), strings.TrimSpace(repo)
+	if !ok || name == "" || strings.Count(repo, "/") != 1 || strings.HasPrefix(repo, "/") || strings.HasSuffix(repo, "/") {
+		return "", "", fmt.Errorf("expected name=owner/repo, got %q", s)
+	}
+	return name, repo, nil
+}
@@ -13,6 +13,16 @@ import (
 )
 
 
+// IsArchive reports whether Extract knows how to unpack the file at path.
+func IsArchive(path string) bool {
+	for _, ext := range []string{".zip", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
 func Extract(src, dest string) error {
 	if strings.HasSuffix(src, ".zip") {
 		return unzip(src, dest)
@@ -94,7 +104,10 @@ func untar(src, dest string) error {
 				return err
 			}
 		case tar.TypeReg:
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR|os.O_TRUNC, os.FileMode(header.Mode))
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,199 @@
+package builder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/user/track/internal/archiver"
+)
+
+// Toolchains that can be detected in a source tree.
+const (
+	Go   = "go"
+	Rust = "rust"
+)
+
+// Options controls a source build.
+type Options struct {
+	Name    string   // binary name to look for
+	Command string   // overrides the default build command
+	Output  string   // built binary relative to the source root
+	Env     []string // extra KEY=VALUE pairs
+}
+
+// FindRoot returns the directory under dir holding go.mod or Cargo.toml,
+// looking at dir itself and one level down (source tarballs usually wrap
+// everything in a single top-level folder), and the detected toolchain.
+func FindRoot(dir string) (string, string, error) {
+	candidates := []string{dir}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			candidates = append(candidates, filepath.Join(dir, e.Name()))
+		}
+	}
+	for _, c := range candidates {
+		if fileExists(filepath.Join(c, "go.mod")) {
+			return c, Go, nil
+		}
+		if fileExists(filepath.Join(c, "Cargo.toml")) {
+			return c, Rust, nil
+		}
+	}
+	return "", "", fmt.Errorf("no go.mod or Cargo.toml found in source")
+}
+
+// Build runs the toolchain in root and returns the path of the built binary.
+func Build(root, toolchain string, opts Options) (string, error) {
+	exe := opts.Name
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+
+	command := opts.Command
+	env := append(os.Environ(), opts.Env...)
+	switch toolchain {
+	case Go:
+		if command == "" {
+			command = "go build -trimpath -o " + exe + " " + goMainPackage(root, opts.Name)
+		}
+		// Vendored modules let the build run without network access.
+		if dirExists(filepath.Join(root, "vendor")) && !hasEnv(opts.Env, "GOFLAGS") {
+			env = append(env, "GOFLAGS=-mod=vendor")
+		}
+	case Rust:
+		if command == "" {
+			command = "cargo build --release"
+			if dirExists(filepath.Join(root, "vendor")) {
+				command += " --offline"
+			}
+		}
+	default:
+		return "", fmt.Errorf("unsupported toolchain '%s'", toolchain)
+	}
+
+	args, err := SplitCommand(command)
+	if err != nil {
+		return "", fmt.Errorf("invalid build command: %w", err)
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("empty build command")
+	}
+	fmt.Printf("Building in %s: %s\n", root, command)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = root
+	cmd.Env = env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("build command failed: %w", err)
+	}
+
+	if opts.Output != "" {
+		out := filepath.Join(root, filepath.FromSlash(opts.Output))
+		if !fileExists(out) {
+			return "", fmt.Errorf("build output '%s' not found", opts.Output)
+		}
+		return out, nil
+	}
+	switch toolchain {
+	case Go:
+		return filepath.Join(root, exe), nil
+	default:
+		return archiver.FindExecutable(filepath.Join(root, "target", "release"), opts.Name, opts.Name)
+	}
+}
+
+// SplitCommand splits a command line into arguments the way a POSIX shell
+// would, without expanding anything: single quotes keep their content
+// as-is, double quotes allow \" and \\, and a backslash outside quotes
+// escapes the next character. So `go build -ldflags "-s -w" .` yields four
+// arguments.
+func SplitCommand(command string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				cur.WriteRune(runes[i])
+			default:
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("trailing backslash in %q", command)
+			}
+			i++
+			cur.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, command)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
+
+// goMainPackage guesses the main package: the module root or cmd/<name>.
+func goMainPackage(root, name string) string {
+	if dirExists(filepath.Join(root, "cmd", name)) {
+		return "./cmd/" + name
+	}
+	return "."
+}
+
+func hasEnv(env []string, key string) bool {
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+func fileExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
+
+func dirExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
@@ -0,0 +1,122 @@
+package builder
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"cargo build --release", []string{"cargo", "build", "--release"}},
+		{`go build -ldflags "-s -w" ./cmd/x`, []string{"go", "build", "-ldflags", "-s -w", "./cmd/x"}},
+		{`go build -ldflags '-X main.version=1.0 -s' .`, []string{"go", "build", "-ldflags", "-X main.version=1.0 -s", "."}},
+		{`make OUT=a\ b`, []string{"make", "OUT=a b"}},
+		{`echo "say \"hi\"" "C:\tools"`, []string{"echo", `say "hi"`, `C:\tools`}},
+		{`  spaced   out  `, []string{"spaced", "out"}},
+		{`empty "" arg`, []string{"empty", "", "arg"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.in)
+		if err != nil {
+			t.Errorf("SplitCommand(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	for _, bad := range []string{`go build "-s`, `it's`, `trailing\`} {
+		if _, err := SplitCommand(bad); err == nil {
+			t.Errorf("SplitCommand(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+// copyFixture copies testdata/<name> to a temp dir, since Build writes the
+// binary into the source tree.
+func copyFixture(t *testing.T, name string) string {
+	t.Helper()
+	src := filepath.Join("testdata", name)
+	dst := filepath.Join(t.TempDir(), name)
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dst
+}
+
+// offlineEnv keeps the build from reaching the network.
+func offlineEnv() []string {
+	return []string{"GOPROXY=off", "GOFLAGS=-mod=vendor", "GOTOOLCHAIN=local"}
+}
+
+func TestBuildVendoredGoModuleOffline(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not available")
+	}
+	root, toolchain, err := FindRoot(copyFixture(t, "vendored"))
+	if err != nil || toolchain != Go {
+		t.Fatalf("FindRoot = %s, %s, %v", root, toolchain, err)
+	}
+
+	t.Run("default command", func(t *testing.T) {
+		bin, err := Build(root, toolchain, Options{Name: "hello", Env: offlineEnv()})
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		if out := run(t, bin); out != "hello from a vendored module dev" {
+			t.Errorf("built binary printed %q", out)
+		}
+	})
+
+	t.Run("quoted ldflags", func(t *testing.T) {
+		out := "hello-custom"
+		if runtime.GOOS == "windows" {
+			out += ".exe"
+		}
+		bin, err := Build(root, toolchain, Options{
+			Name:    "hello",
+			Command: `go build -ldflags "-s -w -X main.version=1.2.3" -o ` + out + ` ./cmd/hello`,
+			Output:  out,
+			Env:     offlineEnv(),
+		})
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		if got := run(t, bin); got != "hello from a vendored module 1.2.3" {
+			t.Errorf("built binary printed %q", got)
+		}
+	})
+}
+
+func run(t *testing.T, bin string) string {
+	t.Helper()
+	out, err := exec.Command(bin).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%s: %v\n%s", bin, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
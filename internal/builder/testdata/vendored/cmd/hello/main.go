@@ -0,0 +1,13 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/greet"
+)
+
+var version = "dev"
+
+func main() {
+	fmt.Println(greet.Hello(), version)
+}
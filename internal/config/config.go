@@ -1,11 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/user/track/internal/fsutil"
+	"github.com/user/track/internal/paths"
 )
 
 var (
@@ -14,9 +19,24 @@ var (
 	mu   sync.Mutex
 )
 
+// Notices receives the warnings and migration notices printed while the
+// config loads. The shim discards them so that the tools it runs keep a
+// clean stderr.
+var Notices io.Writer = os.Stderr
+
+// Release sources a repo can be tracked from.
+const (
+	SourceGitHub = "github"
+	SourceURL    = "url"
+	SourceTags   = "tags"
+)
+
 type Config struct {
-	Global GlobalConfig     `json:"global"`
-	Repos  map[string]*Repo `json:"repos"`
+	SchemaVersion int              `json:"schema_version"`
+	Global        GlobalConfig     `json:"global"`
+	Repos         map[string]*Repo `json:"repos"`
+
+	base []byte // config.json as last read or written, for detecting concurrent changes
 }
 
 type GlobalConfig struct {
@@ -31,12 +51,24 @@ type GlobalConfig struct {
 	DefaultInstallName    string   `json:"default_install_name,omitempty"`
 	MatcherMode           string   `json:"matcher_mode,omitempty"` // "strict" or "relaxed"
 
+	// ShimMode links small scripts instead of symlinks so that each tool
+	// resolves its version from the nearest project manifest (.track.json/.track.toml).
+	ShimMode bool `json:"shim_mode,omitempty"`
+	// LinkVersions keeps every installed version linked as name@version
+	// next to the regular link.
+	LinkVersions bool `json:"link_versions,omitempty"`
+	// DefaultSmokeTest is the smoke_test of repos that set none.
+	DefaultSmokeTest string `json:"default_smoke_test,omitempty"`
+	SmokeTimeout     int    `json:"smoke_timeout,omitempty"` // seconds a smoke test may run, default 10
+
 	Debug bool `json:"debug,omitempty"` // Enable debug output
 }
 
 type Repo struct {
 	Path              string   `json:"-"`
 	InstallName       string   `json:"install_name,omitempty"`
+	Aliases           []string `json:"aliases,omitempty"` // extra names commands accept for this repo
+	Tags              []string `json:"tags,omitempty"`    // groups for --tag selectors, e.g. k8s or work
 	AssetFilter       string   `json:"asset_filter,omitempty"`
 	AssetExclude      string   `json:"asset_exclude,omitempty"`
 	IncludePrerelease bool     `json:"include_prerelease"`
@@ -48,6 +80,46 @@ type Repo struct {
 	FallbackArch      []string `json:"fallback_arch,omitempty"`
 	FallbackOS        []string `json:"fallback_os,omitempty"`
 	MatcherMode       string   `json:"matcher_mode,omitempty"`
+
+	Source       string        `json:"source,omitempty"`        // "github" (default), "url" or "tags"
+	URLTemplate  string        `json:"url_template,omitempty"`  // download URL template for url and tags sources
+	VersionCheck *VersionCheck `json:"version_check,omitempty"` // how to discover the latest version of a url source
+
+	Build *Build `json:"build,omitempty"` // opt-in build from source when no asset matches
+
+	SmokeTest   string   `json:"smoke_test,omitempty"`   // command a new version must pass before it is linked, e.g. "{{.Bin}} --help"; "none" skips it
+	LastFailure *Failure `json:"last_failure,omitempty"` // the last version that failed its smoke test
+}
+
+// DefaultSmokeTest runs the new executable with --version.
+const DefaultSmokeTest = "{{.Bin}} --version"
+
+// SmokeTestNone disables the smoke test of a repo.
+const SmokeTestNone = "none"
+
+// Failure records an install that was rolled back.
+type Failure struct {
+	Version string `json:"version"`
+	Time    string `json:"time"` // RFC 3339
+	Error   string `json:"error"`
+}
+
+// Build configures building a repo from its source tarball. Go (go.mod) and
+// Rust (Cargo.toml) projects are detected automatically.
+type Build struct {
+	Command   string   `json:"command,omitempty"`    // overrides the toolchain's default build command
+	Output    string   `json:"output,omitempty"`     // built binary, relative to the source root
+	SourceURL string   `json:"source_url,omitempty"` // source tarball URL template; defaults to the tag's GitHub tarball
+	Env       []string `json:"env,omitempty"`        // extra KEY=VALUE pairs, e.g. GOPROXY=off or GOFLAGS=-mod=vendor
+	Always    bool     `json:"always,omitempty"`     // build even when a compatible asset exists
+}
+
+// VersionCheck describes how the latest version of a url source is found.
+type VersionCheck struct {
+	Strategy string `json:"strategy"`            // "json", "redirect" or "regex"
+	URL      string `json:"url"`                 // index, "latest" or download page URL
+	JSONPath string `json:"json_path,omitempty"` // dot-separated path for the json strategy, e.g. "current_version"
+	Regex    string `json:"regex,omitempty"`     // version pattern; the first capture group is used if present
 }
 
 func Get() (*Config, error) {
@@ -58,6 +130,10 @@ func Get() (*Config, error) {
 	return cfg, err
 }
 
+// Save writes the config atomically while holding an advisory lock shared
+// with other track processes. If config.json changed on disk since it was
+// loaded, the local changes are merged into the newer content instead of
+// overwriting it. The previous file is kept as a rolling backup.
 func (c *Config) Save() error {
 	mu.Lock()
 	defer mu.Unlock()
@@ -67,12 +143,91 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(c, "", "  ")
+	unlock, err := fsutil.Lock(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := marshal(c)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	return os.WriteFile(path, data, 0644)
+	onDisk, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if c.base != nil && onDisk != nil && !bytes.Equal(onDisk, c.base) {
+		// Another process saved since we loaded: keep its changes and ours.
+		merged, err := merge3(c.base, data, onDisk)
+		if err != nil {
+			return fmt.Errorf("failed to merge concurrent config changes: %w", err)
+		}
+		var mergedCfg Config
+		if err := json.Unmarshal(merged, &mergedCfg); err != nil {
+			return fmt.Errorf("failed to merge concurrent config changes: %w", err)
+		}
+		c.replaceWith(&mergedCfg)
+		if data, err = marshal(c); err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+	}
+
+	if onDisk != nil && !bytes.Equal(onDisk, data) {
+		rotateBackups(path, onDisk, c.Global.BackupCount)
+	}
+	if err := fsutil.WriteFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	c.base = data
+	return nil
+}
+
+// replaceWith copies merged into c, keeping existing *Repo pointers valid for
+// callers that hold on to them.
+func (c *Config) replaceWith(merged *Config) {
+	c.Global = merged.Global
+	if c.Repos == nil {
+		c.Repos = make(map[string]*Repo)
+	}
+	for key, repo := range merged.Repos {
+		repo.Path = key
+		if existing, ok := c.Repos[key]; ok {
+			*existing = *repo
+		} else {
+			c.Repos[key] = repo
+		}
+	}
+	for key := range c.Repos {
+		if _, ok := merged.Repos[key]; !ok {
+			delete(c.Repos, key)
+		}
+	}
+}
+
+func marshal(c *Config) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // keep regexes like "<" readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rotateBackups keeps the last count versions of the config as
+// config.json.1 (newest) to config.json.<count>.
+func rotateBackups(path string, previous []byte, count int) {
+	if count <= 0 {
+		return
+	}
+	os.Remove(fmt.Sprintf("%s.%d", path, count))
+	for i := count - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+	fsutil.WriteFileAtomic(path+".1", previous, 0644)
 }
 
 func loadConfig() (*Config, error) {
@@ -82,6 +237,17 @@ func loadConfig() (*Config, error) {
 	}
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if moved, err := moveLegacyConfig(path); err != nil {
+			return nil, fmt.Errorf("failed to move config from %s: %w", paths.LegacyConfigDir(), err)
+		} else if moved {
+			c, err := loadConfig()
+			if err == nil && filepath.Clean(c.Global.DataDir) == paths.LegacyConfigDir() {
+				if dataDir, err := paths.DataDir(); err == nil {
+					fmt.Fprintf(Notices, "Installed tools are still in %s, which cache cleaners may wipe.\nMove them with: track migrate-data %s\n", c.Global.DataDir, dataDir)
+				}
+			}
+			return c, err
+		}
 		defaultCfg := createDefaultConfig()
 		if err := defaultCfg.Save(); err != nil {
 			return nil, fmt.Errorf("failed to save default config: %w", err)
@@ -96,59 +262,123 @@ func loadConfig() (*Config, error) {
 
 	var c Config
 	if err := json.Unmarshal(data, &c); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, describeJSONError(data, err))
 	}
-
-	return &c, nil
-}
-
-func configPath() (string, error) {
-	var trackDir string
-	if isWindows() {
-		localAppData := os.Getenv("LOCALAPPDATA")
-		if localAppData == "" {
-			return "", fmt.Errorf("LOCALAPPDATA not set")
+	if c.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("config file %s has schema version %d, this track supports up to %d; upgrade track", path, c.SchemaVersion, SchemaVersion)
+	}
+	if c.Repos == nil {
+		c.Repos = make(map[string]*Repo)
+	}
+	for key, repo := range c.Repos {
+		if repo == nil {
+			repo = &Repo{}
+			c.Repos[key] = repo
 		}
-		trackDir = filepath.Join(localAppData, "track")
-	} else {
+		repo.Path = key
+	}
+	c.base = data
 
-		dataDir, err := dataPath()
-		if err != nil {
-			return "", err
+	if c.migrate() {
+		if err := c.Save(); err != nil {
+			return nil, fmt.Errorf("failed to save migrated config: %w", err)
 		}
-		trackDir = dataDir
 	}
-	if err := os.MkdirAll(trackDir, 0755); err != nil {
-		return "", err
+
+	// Problems are reported but not fatal, so that 'track config' can still
+	// open the file to fix them.
+	issues := unknownFields(data)
+	issues = append(issues, c.Validate()...)
+	for _, issue := range issues {
+		fmt.Fprintf(Notices, "Config %s\n", issue)
 	}
-	return filepath.Join(trackDir, "config.json"), nil
+	if len(issues) > 0 {
+		fmt.Fprintf(Notices, "Run 'track config validate' after editing %s to re-check.\n", path)
+	}
+
+	return &c, nil
 }
 
-func isWindows() bool {
-	return os.PathSeparator == '\\'
+// Path returns the location of config.json.
+func Path() (string, error) {
+	return configPath()
 }
 
-func dataPath() (string, error) {
-	dataDir, err := os.UserCacheDir()
+// Dir returns the directory holding config.json.
+func Dir() (string, error) {
+	path, err := configPath()
 	if err != nil {
-		home, _ := os.UserHomeDir()
-		dataDir = filepath.Join(home, ".local", "share")
+		return "", err
 	}
-	trackDir := filepath.Join(dataDir, "track")
-	if err := os.MkdirAll(trackDir, 0755); err != nil {
+	return filepath.Dir(path), nil
+}
+
+func configPath() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
 		return "", err
 	}
-	return trackDir, nil
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// legacyFiles are moved by moveLegacyConfig; installed tools are left where
+// data_dir points.
+var legacyFiles = []string{"config.json", "track.lock"}
+
+// moveLegacyConfig moves config.json (and track.lock and backups) from the
+// directory older track versions used, ~/.cache/track, into the config
+// directory. It reports whether a config was moved.
+func moveLegacyConfig(path string) (bool, error) {
+	legacyDir := paths.LegacyConfigDir()
+	newDir := filepath.Dir(path)
+	if legacyDir == "" || legacyDir == newDir {
+		return false, nil
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, "config.json")); err != nil {
+		return false, nil
+	}
+
+	names := append([]string(nil), legacyFiles...)
+	backups, _ := filepath.Glob(filepath.Join(legacyDir, "config.json.[0-9]*"))
+	for _, b := range backups {
+		names = append(names, filepath.Base(b))
+	}
+	for _, name := range names {
+		from := filepath.Join(legacyDir, name)
+		data, err := os.ReadFile(from)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if err := fsutil.WriteFileAtomic(filepath.Join(newDir, name), data, 0644); err != nil {
+			return false, err
+		}
+		os.Remove(from)
+	}
+	os.Remove(filepath.Join(legacyDir, "config.json.lock"))
+	fmt.Fprintf(Notices, "Moved config from %s to %s.\n", legacyDir, newDir)
+	return true, nil
 }
 
 func createDefaultConfig() *Config {
-	dataPath, _ := dataPath()
+	dataPath, _ := paths.DataDir()
+	os.MkdirAll(dataPath, 0755)
 	return &Config{
+		SchemaVersion: SchemaVersion,
 		Global: GlobalConfig{
 			DataDir:          dataPath,
 			BackupCount:      3,
-			ExcludedPatterns: []string{"\\.deb$", "\\.rpm$", "checksums", "\\.sig$", "\\.asc$"},
+			ExcludedPatterns: defaultExcludedPatterns(),
 		},
 		Repos: make(map[string]*Repo),
 	}
 }
+
+func defaultExcludedPatterns() []string {
+	return []string{"\\.deb$", "\\.rpm$", "checksums", "\\.sig$", "\\.asc$"}
+}
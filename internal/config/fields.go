@@ -0,0 +1,382 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field is one settable key of GlobalConfig or Repo, addressed by its JSON
+// name. Keys of nested objects are dotted, e.g. "version_check.strategy";
+// the object itself is a key too, so that it can be unset as a whole.
+type Field struct {
+	Key      string
+	Kind     string // "string", "bool", "int", "list" or "object"
+	State    bool   // maintained by track itself and read-only
+	Inherits string // global key whose value applies while a repo key is unset
+
+	index []int
+}
+
+// stateFields are recorded by track and cannot be set by hand.
+var stateFields = map[string]bool{
+	"current_version": true,
+	"version_history": true,
+	"last_failure":    true,
+}
+
+// inheritedFrom maps repo keys to the global default used when they are unset.
+var inheritedFrom = map[string]string{
+	"include_prerelease": "default_prerelease",
+	"asset_filter":       "default_asset_filter",
+	"install_name":       "default_install_name",
+	"asset_priority":     "default_asset_priority",
+	"preferred_archives": "preferred_archive_types",
+	"matcher_mode":       "matcher_mode",
+	"smoke_test":         "default_smoke_test",
+}
+
+// fieldAliases keeps the short names 'track set' has always accepted.
+var fieldAliases = map[string]string{
+	"prerelease": "include_prerelease",
+}
+
+// GlobalFields lists the keys of the "global" object.
+func GlobalFields() []Field {
+	return fieldsOf(reflect.TypeOf(GlobalConfig{}), "", nil)
+}
+
+// RepoFields lists the keys of a "repos" entry.
+func RepoFields() []Field {
+	fields := fieldsOf(reflect.TypeOf(Repo{}), "", nil)
+	for i := range fields {
+		fields[i].State = stateFields[strings.Split(fields[i].Key, ".")[0]]
+		fields[i].Inherits = inheritedFrom[fields[i].Key]
+	}
+	return fields
+}
+
+func fieldsOf(t reflect.Type, prefix string, index []int) []Field {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if sf.PkgPath != "" || name == "-" || name == "" {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		key := prefix + name
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Struct {
+			fields = append(fields, Field{Key: key, Kind: "object", index: idx})
+			fields = append(fields, fieldsOf(ft.Elem(), key+".", idx)...)
+			continue
+		}
+		var kind string
+		switch ft.Kind() {
+		case reflect.String:
+			kind = "string"
+		case reflect.Bool:
+			kind = "bool"
+		case reflect.Int:
+			kind = "int"
+		case reflect.Slice:
+			kind = "list"
+		default:
+			continue
+		}
+		fields = append(fields, Field{Key: key, Kind: kind, index: idx})
+	}
+	return fields
+}
+
+// FindField looks up key in fields. Matching ignores case, underscores and
+// dashes, so "AssetFilter", "asset-filter" and "asset_filter" are the same.
+func FindField(fields []Field, key string) (Field, error) {
+	want := normalizeKey(key)
+	if alias, ok := fieldAliases[want]; ok {
+		want = normalizeKey(alias)
+	}
+	for _, f := range fields {
+		if normalizeKey(f.Key) == want {
+			return f, nil
+		}
+	}
+	known := make(map[string]reflect.Type, len(fields))
+	for _, f := range fields {
+		known[f.Key] = nil
+	}
+	if s := suggest(key, known); s != "" {
+		return Field{}, fmt.Errorf("unknown key '%s' (did you mean '%s'?)", key, s)
+	}
+	return Field{}, fmt.Errorf("unknown key '%s'", key)
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
+
+// value walks to f in target (a *GlobalConfig or *Repo). With alloc, nil
+// nested structs are created on the way; otherwise ok is false if one is nil.
+func (f Field) value(target interface{}, alloc bool) (v reflect.Value, ok bool) {
+	v = reflect.ValueOf(target).Elem()
+	for n, i := range f.index {
+		v = v.Field(i)
+		if n < len(f.index)-1 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+	}
+	return v, true
+}
+
+// Get formats f's value in target. set is false when the key is unset, i.e.
+// holds its zero value.
+func (f Field) Get(target interface{}) (value string, set bool) {
+	v, ok := f.value(target, false)
+	if !ok || v.IsZero() {
+		return "", false
+	}
+	switch f.Kind {
+	case "list":
+		return strings.Join(v.Interface().([]string), ","), true
+	case "object":
+		data, _ := json.Marshal(v.Interface())
+		return string(data), true
+	default:
+		return fmt.Sprint(v.Interface()), true
+	}
+}
+
+// Set parses value according to f's kind and stores it in target. Lists are
+// comma-separated or a JSON array; objects are a JSON object.
+func (f Field) Set(target interface{}, value string) error {
+	if f.State {
+		return fmt.Errorf("'%s' is maintained by track and cannot be set", f.Key)
+	}
+	v, _ := f.value(target, true)
+	switch f.Kind {
+	case "string":
+		v.SetString(value)
+	case "bool":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("'%s' expects true or false, got '%s'", f.Key, value)
+		}
+		v.SetBool(b)
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("'%s' expects a number, got '%s'", f.Key, value)
+		}
+		v.SetInt(int64(n))
+	case "list":
+		list, err := parseList(value)
+		if err != nil {
+			return fmt.Errorf("'%s': %w", f.Key, err)
+		}
+		v.Set(reflect.ValueOf(list))
+	case "object":
+		obj := reflect.New(v.Type().Elem())
+		if err := json.Unmarshal([]byte(value), obj.Interface()); err != nil {
+			return fmt.Errorf("'%s' expects a JSON object, e.g. '{...}', or set its keys like '%s.<key>': %v", f.Key, f.Key, err)
+		}
+		v.Set(obj)
+	}
+	return nil
+}
+
+// Unset resets f in target to its zero value. A nested object left empty is
+// removed entirely.
+func (f Field) Unset(target interface{}) error {
+	if f.State {
+		return fmt.Errorf("'%s' is maintained by track and cannot be unset", f.Key)
+	}
+	v, ok := f.value(target, false)
+	if !ok {
+		return nil
+	}
+	v.Set(reflect.Zero(v.Type()))
+	if len(f.index) > 1 {
+		parent := reflect.ValueOf(target).Elem().Field(f.index[0])
+		if parent.Kind() == reflect.Ptr && parent.Elem().IsZero() {
+			parent.Set(reflect.Zero(parent.Type()))
+		}
+	}
+	return nil
+}
+
+func parseList(value string) ([]string, error) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		var list []string
+		if err := json.Unmarshal([]byte(value), &list); err != nil {
+			return nil, fmt.Errorf("invalid JSON list: %v", err)
+		}
+		return list, nil
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list, nil
+}
+
+// SetField sets key on the global settings (repoPath "") or on a tracked
+// repo, rejecting values that make that key invalid. Issues elsewhere in the
+// config, e.g. a url source still missing its url_template, are returned
+// so they can be shown as hints.
+func (c *Config) SetField(repoPath, key, value string) ([]Issue, error) {
+	return c.updateField(repoPath, key, func(f Field, target interface{}) error {
+		return f.Set(target, value)
+	})
+}
+
+// UnsetField resets key on the global settings (repoPath "") or a repo.
+func (c *Config) UnsetField(repoPath, key string) error {
+	_, err := c.updateField(repoPath, key, func(f Field, target interface{}) error {
+		return f.Unset(target)
+	})
+	return err
+}
+
+func (c *Config) updateField(repoPath, key string, update func(Field, interface{}) error) ([]Issue, error) {
+	target, fields, prefix, err := c.scope(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := FindField(fields, key)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, _ := json.Marshal(target)
+	restore := func() {
+		switch t := target.(type) {
+		case *GlobalConfig:
+			*t = GlobalConfig{}
+			json.Unmarshal(snapshot, t)
+		case *Repo:
+			path := t.Path
+			*t = Repo{}
+			json.Unmarshal(snapshot, t)
+			t.Path = path
+		}
+	}
+	if err := update(f, target); err != nil {
+		restore()
+		return nil, err
+	}
+
+	var hints []Issue
+	for _, issue := range c.Validate() {
+		if isIssueFor(issue, prefix+f.Key) && !issue.Warning {
+			restore()
+			return nil, fmt.Errorf("%s: %s", f.Key, issue.Message)
+		}
+		if strings.HasPrefix(issue.Field, strings.TrimSuffix(prefix, ".")) {
+			hints = append(hints, issue)
+		}
+	}
+	return hints, nil
+}
+
+// isIssueFor reports whether issue is about field or one of its elements.
+func isIssueFor(issue Issue, field string) bool {
+	return issue.Field == field || strings.HasPrefix(issue.Field, field+"[") || strings.HasPrefix(issue.Field, field+".")
+}
+
+// scope returns the struct a key is looked up in and the prefix Validate
+// uses for its issues.
+func (c *Config) scope(repoPath string) (target interface{}, fields []Field, prefix string, err error) {
+	if repoPath == "" {
+		return &c.Global, GlobalFields(), "global.", nil
+	}
+	repo, ok := c.Repos[repoPath]
+	if !ok {
+		return nil, nil, "", fmt.Errorf("repository '%s' is not tracked", repoPath)
+	}
+	return repo, RepoFields(), fmt.Sprintf("repos[%q].", repoPath), nil
+}
+
+// Setting is the effective value of one key.
+type Setting struct {
+	Field
+	Value  string
+	Origin string // "", "default" or the global key the value is inherited from
+}
+
+// Settings lists the effective value of every key of the global settings
+// (repoPath "") or a repo, including globals inherited by unset repo keys.
+func (c *Config) Settings(repoPath string) ([]Setting, error) {
+	target, fields, _, err := c.scope(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	globals := GlobalFields()
+	var settings []Setting
+	for _, f := range fields {
+		if f.Kind == "object" {
+			continue // listed key by key
+		}
+		s := Setting{Field: f}
+		var set bool
+		if s.Value, set = f.Get(target); !set {
+			s.Origin = "default"
+			if f.Inherits != "" {
+				if g, err := FindField(globals, f.Inherits); err == nil {
+					if v, ok := g.Get(&c.Global); ok {
+						s.Value, s.Origin = v, "global."+f.Inherits
+					}
+				}
+			}
+		}
+		settings = append(settings, s)
+	}
+	sort.SliceStable(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, nil
+}
+
+// Setting returns the effective value of one key, see Settings.
+func (c *Config) Setting(repoPath, key string) (Setting, error) {
+	_, fields, _, err := c.scope(repoPath)
+	if err != nil {
+		return Setting{}, err
+	}
+	f, err := FindField(fields, key)
+	if err != nil {
+		return Setting{}, err
+	}
+	if f.Kind == "object" {
+		target, _, _, _ := c.scope(repoPath)
+		s := Setting{Field: f}
+		if value, set := f.Get(target); set {
+			s.Value = value
+		} else {
+			s.Origin = "default"
+		}
+		return s, nil
+	}
+	settings, err := c.Settings(repoPath)
+	if err != nil {
+		return Setting{}, err
+	}
+	for _, s := range settings {
+		if s.Key == f.Key {
+			return s, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown key '%s'", key)
+}
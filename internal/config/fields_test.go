@@ -0,0 +1,212 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindField(t *testing.T) {
+	tests := []struct {
+		key, want, kind string
+	}{
+		{"asset_filter", "asset_filter", "string"},
+		{"AssetFilter", "asset_filter", "string"},
+		{"asset-filter", "asset_filter", "string"},
+		{"prerelease", "include_prerelease", "bool"},
+		{"build", "build", "object"},
+		{"build.command", "build.command", "string"},
+		{"version_check.strategy", "version_check.strategy", "string"},
+		{"aliases", "aliases", "list"},
+	}
+	for _, tt := range tests {
+		f, err := FindField(RepoFields(), tt.key)
+		if err != nil {
+			t.Errorf("FindField(%q): %v", tt.key, err)
+			continue
+		}
+		if f.Key != tt.want || f.Kind != tt.kind {
+			t.Errorf("FindField(%q) = %s (%s), want %s (%s)", tt.key, f.Key, f.Kind, tt.want, tt.kind)
+		}
+	}
+	if _, err := FindField(RepoFields(), "asset_filtr"); err == nil || err.Error() != "unknown key 'asset_filtr' (did you mean 'asset_filter'?)" {
+		t.Errorf("FindField(asset_filtr) error = %v", err)
+	}
+	for _, key := range []string{"current_version", "version_history", "last_failure", "last_failure.error"} {
+		if f, _ := FindField(RepoFields(), key); !f.State {
+			t.Errorf("%s is not a state field", key)
+		}
+	}
+}
+
+func TestFieldSetGet(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       string // what Get returns afterwards
+		check      func(*Repo) bool
+	}{
+		{"asset_filter", "linux", "linux", func(r *Repo) bool { return r.AssetFilter == "linux" }},
+		{"prerelease", "true", "true", func(r *Repo) bool { return r.IncludePrerelease }},
+		{"aliases", "rg, grep", "rg,grep", func(r *Repo) bool { return reflect.DeepEqual(r.Aliases, []string{"rg", "grep"}) }},
+		{"aliases", `["a,b", "c"]`, "a,b,c", func(r *Repo) bool { return reflect.DeepEqual(r.Aliases, []string{"a,b", "c"}) }},
+		{"build.command", "make tool", "make tool", func(r *Repo) bool { return r.Build != nil && r.Build.Command == "make tool" }},
+		{"build", `{"always": true}`, `{"always":true}`, func(r *Repo) bool { return r.Build != nil && r.Build.Always }},
+		{"version_check.url", "https://example.com", "https://example.com", func(r *Repo) bool {
+			return r.VersionCheck != nil && r.VersionCheck.URL == "https://example.com"
+		}},
+	}
+	for _, tt := range tests {
+		repo := &Repo{}
+		f, err := FindField(RepoFields(), tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Set(repo, tt.value); err != nil {
+			t.Errorf("Set(%s, %q): %v", tt.key, tt.value, err)
+			continue
+		}
+		if !tt.check(repo) {
+			t.Errorf("Set(%s, %q) stored %+v", tt.key, tt.value, repo)
+		}
+		if got, set := f.Get(repo); !set || got != tt.want {
+			t.Errorf("Get(%s) = %q, %v, want %q", tt.key, got, set, tt.want)
+		}
+	}
+
+	smokeTimeout, _ := FindField(GlobalFields(), "smoke_timeout")
+	global := &GlobalConfig{}
+	if err := smokeTimeout.Set(global, "30"); err != nil || global.SmokeTimeout != 30 {
+		t.Errorf("Set(smoke_timeout, 30) = %v, stored %d", err, global.SmokeTimeout)
+	}
+}
+
+func TestFieldSetErrors(t *testing.T) {
+	tests := []struct {
+		key, value string
+		global     bool
+	}{
+		{"prerelease", "yes please", false},
+		{"smoke_timeout", "ten", true},
+		{"aliases", `["unterminated`, false},
+		{"build", `[1]`, false},
+		{"current_version", "1.0.0", false},
+	}
+	for _, tt := range tests {
+		fields, target := RepoFields(), interface{}(&Repo{})
+		if tt.global {
+			fields, target = GlobalFields(), &GlobalConfig{}
+		}
+		f, err := FindField(fields, tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Set(target, tt.value); err == nil {
+			t.Errorf("Set(%s, %q) succeeded, want an error", tt.key, tt.value)
+		}
+	}
+}
+
+func TestFieldUnset(t *testing.T) {
+	tests := []struct {
+		key   string
+		check func(*Repo) bool
+	}{
+		{"asset_filter", func(r *Repo) bool { return r.AssetFilter == "" }},
+		{"aliases", func(r *Repo) bool { return r.Aliases == nil }},
+		// The last key of an object removes the object.
+		{"version_check.url", func(r *Repo) bool { return r.VersionCheck == nil }},
+		// One key of several leaves the object in place.
+		{"build.command", func(r *Repo) bool { return r.Build != nil && r.Build.Command == "" && r.Build.Always }},
+		{"build", func(r *Repo) bool { return r.Build == nil }},
+	}
+	for _, tt := range tests {
+		repo := &Repo{
+			AssetFilter:  "linux",
+			Aliases:      []string{"rg"},
+			VersionCheck: &VersionCheck{URL: "https://example.com"},
+			Build:        &Build{Command: "make", Always: true},
+		}
+		f, err := FindField(RepoFields(), tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Unset(repo); err != nil {
+			t.Errorf("Unset(%s): %v", tt.key, err)
+			continue
+		}
+		if !tt.check(repo) {
+			t.Errorf("Unset(%s) left %+v", tt.key, repo)
+		}
+		if _, set := f.Get(repo); set {
+			t.Errorf("Get(%s) after Unset reports it set", tt.key)
+		}
+	}
+
+	// Unsetting a key of a missing object is a no-op.
+	f, _ := FindField(RepoFields(), "build.command")
+	if err := f.Unset(&Repo{}); err != nil {
+		t.Errorf("Unset(build.command) on a repo without build: %v", err)
+	}
+	f, _ = FindField(RepoFields(), "current_version")
+	if err := f.Unset(&Repo{CurrentVersion: "1.0.0"}); err == nil {
+		t.Error("Unset(current_version) succeeded, want an error")
+	}
+}
+
+func TestSettingsInheritance(t *testing.T) {
+	c := &Config{
+		Global: GlobalConfig{DefaultAssetFilter: "musl", DefaultSmokeTest: "{{.Bin}} version"},
+		Repos: map[string]*Repo{
+			"acme/tool":  {Path: "acme/tool", AssetFilter: "gnu"},
+			"acme/other": {Path: "acme/other"},
+		},
+	}
+	tests := []struct {
+		repo, key, value, origin string
+	}{
+		{"acme/tool", "asset_filter", "gnu", ""},
+		{"acme/other", "asset_filter", "musl", "global.default_asset_filter"},
+		{"acme/other", "smoke_test", "{{.Bin}} version", "global.default_smoke_test"},
+		{"acme/other", "install_name", "", "default"},
+		{"acme/other", "build", "", "default"},
+		{"", "default_asset_filter", "musl", ""},
+	}
+	for _, tt := range tests {
+		s, err := c.Setting(tt.repo, tt.key)
+		if err != nil {
+			t.Errorf("Setting(%q, %s): %v", tt.repo, tt.key, err)
+			continue
+		}
+		if s.Value != tt.value || s.Origin != tt.origin {
+			t.Errorf("Setting(%q, %s) = %q from %q, want %q from %q", tt.repo, tt.key, s.Value, s.Origin, tt.value, tt.origin)
+		}
+	}
+
+	settings, err := c.Settings("acme/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range settings {
+		if s.Kind == "object" {
+			t.Errorf("Settings lists object key %s; its keys are listed instead", s.Key)
+		}
+	}
+}
+
+func TestUpdateFieldRollsBackInvalidValues(t *testing.T) {
+	c := &Config{Repos: map[string]*Repo{"acme/tool": {Path: "acme/tool", AssetFilter: "gnu"}}}
+	if _, err := c.SetField("acme/tool", "asset_filter", "(unclosed"); err == nil {
+		t.Fatal("SetField accepted an invalid regex")
+	}
+	if got := c.Repos["acme/tool"].AssetFilter; got != "gnu" {
+		t.Errorf("asset_filter = %q after a rejected SetField, want gnu", got)
+	}
+	if err := c.UnsetField("acme/tool", "asset_filter"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Repos["acme/tool"].AssetFilter; got != "" {
+		t.Errorf("asset_filter = %q after UnsetField", got)
+	}
+	if _, err := c.SetField("acme/missing", "asset_filter", "x"); err == nil {
+		t.Error("SetField on an untracked repo succeeded")
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// absent marks a key missing from one side of a merge.
+type absent struct{}
+
+// merge3 performs a three-way merge of JSON documents: changes made in ours
+// since base are applied on top of theirs. Objects are merged key by key;
+// when both sides changed the same scalar or list, ours wins.
+func merge3(base, ours, theirs []byte) ([]byte, error) {
+	var b, o, t interface{}
+	if err := json.Unmarshal(base, &b); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ours, &o); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(theirs, &t); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergeValue(b, o, t))
+}
+
+func mergeValue(base, ours, theirs interface{}) interface{} {
+	if reflect.DeepEqual(ours, base) {
+		return theirs
+	}
+	if reflect.DeepEqual(theirs, base) {
+		return ours
+	}
+	om, oursIsMap := ours.(map[string]interface{})
+	tm, theirsIsMap := theirs.(map[string]interface{})
+	if !oursIsMap || !theirsIsMap {
+		return ours
+	}
+	bm, _ := base.(map[string]interface{})
+
+	merged := make(map[string]interface{})
+	keys := make(map[string]bool)
+	for _, m := range []map[string]interface{}{bm, om, tm} {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	for k := range keys {
+		v := mergeValue(lookup(bm, k), lookup(om, k), lookup(tm, k))
+		if _, missing := v.(absent); !missing {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func lookup(m map[string]interface{}, key string) interface{} {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return absent{}
+}
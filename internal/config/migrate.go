@@ -0,0 +1,39 @@
+package config
+
+import "strings"
+
+// SchemaVersion is the config layout written by this version of track.
+// Bump it and append to migrations when a change needs existing configs
+// rewritten.
+const SchemaVersion = 1
+
+// migrations[i] upgrades a config from schema version i to i+1.
+var migrations = []func(c *Config){
+	// 0 -> 1: configs written before schema_version existed.
+	func(c *Config) {
+		if c.Global.BackupCount == 0 {
+			c.Global.BackupCount = 3
+		}
+		if c.Global.ExcludedPatterns == nil {
+			c.Global.ExcludedPatterns = defaultExcludedPatterns()
+		}
+		c.Global.MatcherMode = strings.ToLower(strings.TrimSpace(c.Global.MatcherMode))
+		for _, repo := range c.Repos {
+			if repo != nil {
+				repo.MatcherMode = strings.ToLower(strings.TrimSpace(repo.MatcherMode))
+			}
+		}
+	},
+}
+
+// migrate upgrades c to SchemaVersion and reports whether anything ran.
+func (c *Config) migrate() bool {
+	if c.SchemaVersion >= SchemaVersion {
+		return false
+	}
+	for v := c.SchemaVersion; v < SchemaVersion; v++ {
+		migrations[v](c)
+	}
+	c.SchemaVersion = SchemaVersion
+	return true
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InstallName is the link name of a repo: its install_name, else the
+// rendered default_install_name, else the repo name.
+func (g *GlobalConfig) InstallName(repoPath string, repo *Repo) string {
+	if repo != nil && repo.InstallName != "" {
+		return repo.InstallName
+	}
+	owner, name, _ := strings.Cut(repoPath, "/")
+	if g.DefaultInstallName != "" {
+		if rendered, err := RenderInstallName(g.DefaultInstallName, owner, name); err == nil && rendered != "" {
+			return rendered
+		}
+	}
+	return name
+}
+
+// SortedRepos returns the tracked repo paths in 'track list' order.
+func (c *Config) SortedRepos() []string {
+	keys := make([]string, 0, len(c.Repos))
+	for k := range c.Repos {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RepoNames lists the names a repo can be referred to by besides
+// owner/repo: its repo name, install name and aliases.
+func (c *Config) RepoNames(repoPath string) []string {
+	_, name, _ := strings.Cut(repoPath, "/")
+	names := []string{name}
+	add := func(n string) {
+		for _, have := range names {
+			if strings.EqualFold(have, n) {
+				return
+			}
+		}
+		names = append(names, n)
+	}
+	add(c.Global.InstallName(repoPath, c.Repos[repoPath]))
+	if repo := c.Repos[repoPath]; repo != nil {
+		for _, a := range repo.Aliases {
+			add(a)
+		}
+	}
+	return names
+}
+
+// Resolve maps references to tracked repos, in order and without
+// duplicates. A reference is, in order of precedence:
+//
+//   - a glob such as "charmbracelet/*" or "*-lsp", matched against
+//     owner/repo and every name of a repo
+//   - owner/repo
+//   - an alias, install name or repo name
+//   - a list number, only if numbers is true (interactive use)
+//   - a unique prefix of any of the above
+//
+// Matching ignores case. A reference matching nothing, or a name or prefix
+// shared by several repos, is an error.
+func (c *Config) Resolve(refs []string, numbers bool) ([]string, error) {
+	var repos []string
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		matches, err := c.resolve(ref, numbers)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if !seen[m] {
+				seen[m] = true
+				repos = append(repos, m)
+			}
+		}
+	}
+	return repos, nil
+}
+
+// ResolveOne is Resolve for a single reference that must name one repo.
+func (c *Config) ResolveOne(ref string, numbers bool) (string, error) {
+	repos, err := c.resolve(ref, numbers)
+	if err != nil {
+		return "", err
+	}
+	if len(repos) > 1 {
+		return "", fmt.Errorf("'%s' matches %d repositories (%s); name exactly one", ref, len(repos), strings.Join(repos, ", "))
+	}
+	return repos[0], nil
+}
+
+// ByPrefix reports whether ref names a repo only as a prefix: it is not a
+// glob, an owner/repo, a name or alias, or (if numbers is true) a list
+// number. Commands that delete something confirm such references.
+func (c *Config) ByPrefix(ref string, numbers bool) bool {
+	if strings.ContainsAny(ref, "*?[") {
+		return false
+	}
+	if _, err := strconv.Atoi(ref); err == nil && numbers {
+		return false
+	}
+	for _, k := range c.SortedRepos() {
+		for _, name := range append([]string{k}, c.RepoNames(k)...) {
+			if strings.EqualFold(name, ref) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *Config) resolve(ref string, numbers bool) ([]string, error) {
+	keys := c.SortedRepos()
+	lower := strings.ToLower(ref)
+
+	if strings.ContainsAny(ref, "*?[") {
+		if _, err := path.Match(lower, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %v", ref, err)
+		}
+		var matches []string
+		for _, k := range keys {
+			for _, name := range append([]string{k}, c.RepoNames(k)...) {
+				if ok, _ := path.Match(lower, strings.ToLower(name)); ok {
+					matches = append(matches, k)
+					break
+				}
+			}
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no tracked repository matches '%s'", ref)
+		}
+		return matches, nil
+	}
+
+	for _, k := range keys {
+		if strings.EqualFold(k, ref) {
+			return []string{k}, nil
+		}
+	}
+
+	var named []string
+	for _, k := range keys {
+		for _, name := range c.RepoNames(k) {
+			if strings.EqualFold(name, ref) {
+				named = append(named, k)
+				break
+			}
+		}
+	}
+	if len(named) == 1 {
+		return named, nil
+	}
+	if len(named) > 1 {
+		return nil, fmt.Errorf("'%s' is ambiguous: %s (use owner/repo or add an alias)", ref, strings.Join(named, ", "))
+	}
+
+	if n, err := strconv.Atoi(ref); err == nil {
+		if !numbers {
+			return nil, fmt.Errorf("list numbers like '%s' are only accepted interactively; use owner/repo, a name or an alias", ref)
+		}
+		if n < 1 || n > len(keys) {
+			return nil, fmt.Errorf("invalid repository number %d (there are %d)", n, len(keys))
+		}
+		return []string{keys[n-1]}, nil
+	}
+
+	var prefixed []string
+	for _, k := range keys {
+		for _, name := range append([]string{k}, c.RepoNames(k)...) {
+			if strings.HasPrefix(strings.ToLower(name), lower) {
+				prefixed = append(prefixed, k)
+				break
+			}
+		}
+	}
+	switch len(prefixed) {
+	case 0:
+		return nil, fmt.Errorf("repository '%s' is not tracked", ref)
+	case 1:
+		return prefixed, nil
+	default:
+		return nil, fmt.Errorf("'%s' is ambiguous: %s", ref, strings.Join(prefixed, ", "))
+	}
+}
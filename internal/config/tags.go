@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TagSelector picks repos by tag. A repo is selected if it has any of the
+// Include tags (or Include is empty) and none of the Exclude tags.
+type TagSelector struct {
+	Include []string
+	Exclude []string
+}
+
+// ParseTagSelector parses --tag values such as "k8s", "git,shell" or
+// "!experimental".
+func ParseTagSelector(values []string) (TagSelector, error) {
+	var sel TagSelector
+	for _, v := range values {
+		for _, t := range strings.Split(v, ",") {
+			t = strings.TrimSpace(t)
+			neg := strings.HasPrefix(t, "!")
+			t = NormalizeTag(strings.TrimPrefix(t, "!"))
+			if err := checkTag(t); err != nil {
+				return TagSelector{}, err
+			}
+			if neg {
+				sel.Exclude = append(sel.Exclude, t)
+			} else {
+				sel.Include = append(sel.Include, t)
+			}
+		}
+	}
+	return sel, nil
+}
+
+// Empty reports whether the selector selects every repo.
+func (s TagSelector) Empty() bool {
+	return len(s.Include) == 0 && len(s.Exclude) == 0
+}
+
+// Matches reports whether repo is selected.
+func (s TagSelector) Matches(repo *Repo) bool {
+	if repo == nil {
+		return false
+	}
+	for _, t := range s.Exclude {
+		if repo.HasTag(t) {
+			return false
+		}
+	}
+	if len(s.Include) == 0 {
+		return true
+	}
+	for _, t := range s.Include {
+		if repo.HasTag(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s TagSelector) String() string {
+	parts := append([]string(nil), s.Include...)
+	for _, t := range s.Exclude {
+		parts = append(parts, "!"+t)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Select returns the repos among repoPaths that s selects, keeping order.
+func (c *Config) Select(repoPaths []string, s TagSelector) []string {
+	if s.Empty() {
+		return repoPaths
+	}
+	var selected []string
+	for _, p := range repoPaths {
+		if s.Matches(c.Repos[p]) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// Tags lists every tag in use, sorted.
+func (c *Config) Tags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, r := range c.Repos {
+		if r == nil {
+			continue
+		}
+		for _, t := range r.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// NormalizeTag lower-cases a tag so "K8s" and "k8s" are the same.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func checkTag(tag string) error {
+	if tag == "" || strings.ContainsAny(tag, ",! \t/") {
+		return fmt.Errorf("invalid tag %q: tags are single words without ',', '!', '/' or spaces", tag)
+	}
+	return nil
+}
+
+// HasTag reports whether the repo is tagged with tag.
+func (r *Repo) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag tags the repo, reporting whether the tag is new.
+func (r *Repo) AddTag(tag string) (bool, error) {
+	tag = NormalizeTag(tag)
+	if err := checkTag(tag); err != nil {
+		return false, err
+	}
+	if r.HasTag(tag) {
+		return false, nil
+	}
+	r.Tags = append(r.Tags, tag)
+	sort.Strings(r.Tags)
+	return true, nil
+}
+
+// RemoveTag untags the repo, reporting whether it had the tag.
+func (r *Repo) RemoveTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for i, t := range r.Tags {
+		if t == tag {
+			r.Tags = append(r.Tags[:i], r.Tags[i+1:]...)
+			if len(r.Tags) == 0 {
+				r.Tags = nil
+			}
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,393 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// MatcherModes are the accepted values of matcher_mode.
+var MatcherModes = []string{"strict", "relaxed"}
+
+// VersionStrategies are the accepted values of version_check.strategy.
+var VersionStrategies = []string{"json", "redirect", "regex"}
+
+// Issue is a problem found in the config file.
+type Issue struct {
+	Field   string // e.g. repos["acme/tool"].asset_filter
+	Message string
+	Warning bool // true for problems track can work around, like unknown fields
+}
+
+func (i Issue) String() string {
+	level := "error"
+	if i.Warning {
+		level = "warning"
+	}
+	return fmt.Sprintf("%s: %s: %s", level, i.Field, i.Message)
+}
+
+// HasErrors reports whether any issue is an error rather than a warning.
+func HasErrors(issues []Issue) bool {
+	for _, i := range issues {
+		if !i.Warning {
+			return true
+		}
+	}
+	return false
+}
+
+// Check parses raw config.json content and reports unknown fields and
+// semantic problems. A JSON syntax or type error is returned as err.
+func Check(data []byte) ([]Issue, error) {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, describeJSONError(data, err)
+	}
+	issues := unknownFields(data)
+	issues = append(issues, c.Validate()...)
+	return issues, nil
+}
+
+// Validate checks values json.Unmarshal accepts but track cannot use.
+func (c *Config) Validate() []Issue {
+	var issues []Issue
+	add := func(field, format string, args ...interface{}) {
+		issues = append(issues, Issue{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if c.SchemaVersion > SchemaVersion {
+		add("schema_version", "version %d is newer than this track supports (%d); upgrade track", c.SchemaVersion, SchemaVersion)
+	}
+
+	g := c.Global
+	if g.BackupCount < 0 {
+		add("global.backup_count", "must not be negative, got %d", g.BackupCount)
+	}
+	checkRegex(add, "global.default_asset_filter", g.DefaultAssetFilter)
+	for i, p := range g.ExcludedPatterns {
+		checkRegex(add, fmt.Sprintf("global.excluded_patterns[%d]", i), p)
+	}
+	checkOneOf(add, "global.matcher_mode", g.MatcherMode, MatcherModes)
+	if g.DefaultInstallName != "" {
+		if name, err := RenderInstallName(g.DefaultInstallName, "owner", "repo"); err != nil {
+			add("global.default_install_name", "invalid template: %v", err)
+		} else if strings.ContainsAny(name, `/\`) || name == "" {
+			add("global.default_install_name", "must render to a file name, got %q", name)
+		} else if other, _ := RenderInstallName(g.DefaultInstallName, "owner", "repo2"); other == name {
+			// Every repo without install_name would link under the same
+			// name and overwrite the others' links.
+			add("global.default_install_name", "must use {{.Name}}: it renders %q for both owner/repo and owner/repo2, so their links would collide", name)
+		}
+	}
+
+	if g.SmokeTimeout < 0 {
+		add("global.smoke_timeout", "must not be negative, got %d", g.SmokeTimeout)
+	}
+	checkSmokeTest(add, "global.default_smoke_test", g.DefaultSmokeTest)
+
+	keys := make([]string, 0, len(c.Repos))
+	for key := range c.Repos {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		repo := c.Repos[key]
+		prefix := fmt.Sprintf("repos[%q]", key)
+		if repo == nil {
+			add(prefix, "entry is null")
+			continue
+		}
+		if parts := strings.Split(key, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			add(prefix, "key must be in the form owner/repo")
+		}
+		checkRegex(add, prefix+".asset_filter", repo.AssetFilter)
+		checkRegex(add, prefix+".asset_exclude", repo.AssetExclude)
+		checkOneOf(add, prefix+".matcher_mode", repo.MatcherMode, MatcherModes)
+		if strings.ContainsAny(repo.InstallName, `/\`) {
+			add(prefix+".install_name", "must be a file name, not a path: %q", repo.InstallName)
+		}
+		checkSmokeTest(add, prefix+".smoke_test", repo.SmokeTest)
+		for i, t := range repo.Tags {
+			if err := checkTag(t); err != nil {
+				add(fmt.Sprintf("%s.tags[%d]", prefix, i), "%v", err)
+			} else if t != NormalizeTag(t) {
+				add(fmt.Sprintf("%s.tags[%d]", prefix, i), "must be lower-case, got %q", t)
+			}
+		}
+		for i, a := range repo.Aliases {
+			field := fmt.Sprintf("%s.aliases[%d]", prefix, i)
+			if _, err := strconv.Atoi(a); err == nil || a == "" || strings.ContainsAny(a, "/\\*?[ ") {
+				add(field, "must be a non-numeric name without '/', spaces or glob characters, got %q", a)
+				continue
+			}
+			for _, other := range keys {
+				if other == key || c.Repos[other] == nil {
+					continue
+				}
+				for _, name := range c.RepoNames(other) {
+					if strings.EqualFold(name, a) {
+						add(field, "%q is already a name of %s", a, other)
+					}
+				}
+			}
+		}
+
+		switch repo.Source {
+		case "", SourceGitHub:
+		case SourceURL:
+			if repo.URLTemplate == "" {
+				add(prefix+".url_template", "is required for source %q", repo.Source)
+			}
+			if repo.VersionCheck == nil {
+				add(prefix+".version_check", "is required for source %q", repo.Source)
+			}
+		case SourceTags:
+			if repo.URLTemplate == "" {
+				add(prefix+".url_template", "is required for source %q", repo.Source)
+			}
+		default:
+			add(prefix+".source", "unknown source %q (use %s, %s or %s)", repo.Source, SourceGitHub, SourceURL, SourceTags)
+		}
+		checkTemplate(add, prefix+".url_template", repo.URLTemplate)
+
+		if vc := repo.VersionCheck; vc != nil {
+			checkOneOf(add, prefix+".version_check.strategy", vc.Strategy, VersionStrategies)
+			if vc.Strategy == "" {
+				add(prefix+".version_check.strategy", "is required (use %s)", strings.Join(VersionStrategies, ", "))
+			}
+			if vc.URL == "" {
+				add(prefix+".version_check.url", "is required")
+			}
+			if vc.Strategy == "json" && vc.JSONPath == "" {
+				add(prefix+".version_check.json_path", "is required for the json strategy")
+			}
+			checkRegex(add, prefix+".version_check.regex", vc.Regex)
+		}
+
+		if b := repo.Build; b != nil {
+			checkTemplate(add, prefix+".build.source_url", b.SourceURL)
+			for i, kv := range b.Env {
+				if !strings.Contains(kv, "=") {
+					add(fmt.Sprintf("%s.build.env[%d]", prefix, i), "must be KEY=VALUE, got %q", kv)
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// RenderSmokeTest expands a smoke_test template such as "{{.Bin}} --version"
+// into the command's arguments. Arguments are split before expansion, so
+// paths with spaces stay one argument.
+func RenderSmokeTest(tmpl, bin, tag string) ([]string, error) {
+	data := struct{ Bin, Tag, Version string }{bin, tag, strings.TrimPrefix(tag, "v")}
+	var args []string
+	for _, field := range strings.Fields(tmpl) {
+		t, err := template.New("smoke_test").Option("missingkey=error").Parse(field)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return nil, err
+		}
+		args = append(args, buf.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+	return args, nil
+}
+
+// RenderInstallName expands a default_install_name template such as
+// "{{.Name}}-cli" for one repo.
+func RenderInstallName(tmpl, owner, name string) (string, error) {
+	t, err := template.New("default_install_name").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, struct{ Owner, Name string }{owner, name}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+func checkSmokeTest(add func(string, string, ...interface{}), field, tmpl string) {
+	if tmpl == "" || tmpl == SmokeTestNone {
+		return
+	}
+	if _, err := RenderSmokeTest(tmpl, "bin", "v1.0.0"); err != nil {
+		add(field, "invalid command %q: %v", tmpl, err)
+	}
+}
+
+func checkRegex(add func(string, string, ...interface{}), field, pattern string) {
+	if pattern == "" {
+		return
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		add(field, "invalid regex %q: %v", pattern, strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+	}
+}
+
+func checkOneOf(add func(string, string, ...interface{}), field, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	add(field, "unknown value %q (use %s)", value, strings.Join(allowed, " or "))
+}
+
+func checkTemplate(add func(string, string, ...interface{}), field, text string) {
+	if text == "" {
+		return
+	}
+	if _, err := template.New(field).Parse(text); err != nil {
+		add(field, "invalid template: %v", err)
+	}
+}
+
+// unknownFields walks the raw JSON alongside the Config type and reports
+// keys that json.Unmarshal would silently drop.
+func unknownFields(data []byte) []Issue {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	var issues []Issue
+	walkFields(raw, reflect.TypeOf(Config{}), "", &issues)
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Field < issues[j].Field })
+	return issues
+}
+
+func walkFields(raw interface{}, t reflect.Type, path string, issues *[]Issue) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, value := range obj {
+			field := joinPath(path, key)
+			ft, known := fields[key]
+			if !known {
+				msg := "unknown field, ignored"
+				if s := suggest(key, fields); s != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", s)
+				}
+				*issues = append(*issues, Issue{Field: field, Message: msg, Warning: true})
+				continue
+			}
+			walkFields(value, ft, field, issues)
+		}
+	case reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range obj {
+			walkFields(value, t.Elem(), fmt.Sprintf("%s[%q]", path, key), issues)
+		}
+	}
+}
+
+// jsonFields maps the JSON names of t's serialized fields to their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// suggest returns the known field closest to key, if it is close enough to
+// be a likely typo.
+func suggest(key string, fields map[string]reflect.Type) string {
+	normalized := strings.ToLower(strings.ReplaceAll(key, "_", ""))
+	best, bestDist := "", 3
+	for name := range fields {
+		if strings.ToLower(strings.ReplaceAll(name, "_", "")) == normalized {
+			return name
+		}
+		if d := levenshtein(key, name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// describeJSONError adds the line and column to JSON syntax and type errors.
+func describeJSONError(data []byte, err error) error {
+	var offset int64
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+		if e.Field != "" {
+			err = fmt.Errorf("field %s: cannot use JSON %s as %s", e.Field, e.Value, e.Type)
+		}
+	default:
+		return err
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	col := offset - int64(bytes.LastIndexByte(data[:offset], '\n'))
+	return fmt.Errorf("line %d, column %d: %w", line, col, err)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/google/go-github/v55/github"
 	"golang.org/x/oauth2"
@@ -15,7 +16,12 @@ type Client struct {
 }
 
 
+// NewClient returns a GitHub client authenticated with token, or with
+// GITHUB_TOKEN if token is empty (which 'track --token' also sets).
 func NewClient(ctx context.Context, token string) *Client {
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
 	var tc *http.Client
 	if token != "" {
 		ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
@@ -69,6 +75,27 @@ func (c *Client) ListReleases(ctx context.Context, owner, repo string, limit int
 }
 
 
+// ListTags returns up to limit tag names of a repository, following pagination.
+func (c *Client) ListTags(ctx context.Context, owner, repo string, limit int) ([]string, error) {
+	var names []string
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		tags, resp, err := c.Repositories.ListTags(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list tags: %w", err)
+		}
+		for _, t := range tags {
+			names = append(names, t.GetName())
+		}
+		if resp.NextPage == 0 || len(names) >= limit {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return names, nil
+}
+
+
 func (c *Client) SearchRepos(ctx context.Context, query string, limit int) (*github.RepositoriesSearchResult, error) {
 	opts := &github.SearchOptions{
 		Sort:        "stars",
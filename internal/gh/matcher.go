@@ -10,7 +10,31 @@ import (
 	"github.com/user/track/internal/config"
 )
 
+// platformKeyword matches an OS or architecture in a lower-cased asset
+// name, whichever platform it is for.
+var platformKeyword = regexp.MustCompile(`(?:^|[^a-z0-9])(linux|darwin|macos|mac|osx|apple|windows|win|freebsd|openbsd|netbsd|android|amd64|x86_64|x86-64|x64|x86|386|i386|i686|arm64|aarch64|armv\d+\w*|armhf|armel|arm|riscv64|ppc64le|ppc64|s390x|mips\w*|universal)\d*(?:[^a-z0-9]|$)`)
+
 func FindCompatibleAsset(release *github.RepositoryRelease, repoCfg *config.Repo, globalCfg *config.GlobalConfig) (*github.ReleaseAsset, error) {
+	best, _, err := findCompatibleAsset(release, repoCfg, globalCfg)
+	return best, err
+}
+
+// AmbiguousAssets lists the assets FindCompatibleAsset had to choose
+// between by their order in the release alone, since nothing else ranked
+// one above the others. It returns nil if the choice was clear, failed, or
+// the repo's own asset_filter made it.
+func AmbiguousAssets(release *github.RepositoryRelease, repoCfg *config.Repo, globalCfg *config.GlobalConfig) []*github.ReleaseAsset {
+	if repoCfg.AssetFilter != "" {
+		return nil
+	}
+	_, tied, err := findCompatibleAsset(release, repoCfg, globalCfg)
+	if err != nil || len(tied) < 2 {
+		return nil
+	}
+	return tied
+}
+
+func findCompatibleAsset(release *github.RepositoryRelease, repoCfg *config.Repo, globalCfg *config.GlobalConfig) (*github.ReleaseAsset, []*github.ReleaseAsset, error) {
 
 	assetPriority := repoCfg.AssetPriority
 	if len(assetPriority) == 0 && globalCfg != nil {
@@ -34,51 +58,34 @@ func FindCompatibleAsset(release *github.RepositoryRelease, repoCfg *config.Repo
 	if assetFilter == "" && globalCfg != nil {
 		assetFilter = globalCfg.DefaultAssetFilter
 	}
-	assetExclude := repoCfg.AssetExclude
 
-	var filterRe, excludeRe *regexp.Regexp
+	var filterRe, ownFilterRe *regexp.Regexp
 	if assetFilter != "" {
-		filterRe, _ = regexp.Compile(assetFilter)
+		var err error
+		if filterRe, err = regexp.Compile(assetFilter); err != nil {
+			return nil, nil, fmt.Errorf("invalid asset_filter %q: %w", assetFilter, err)
+		}
+		if repoCfg.AssetFilter != "" {
+			ownFilterRe = filterRe
+		}
 	}
-	if assetExclude != "" {
-		excludeRe, _ = regexp.Compile(assetExclude)
+	rules, err := ExcludeRules(repoCfg, globalCfg)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	osChecks, archChecks := getSystemKeywords()
 	strictOS := runtime.GOOS
 
-	matchesPriority := func(name string, priorities []string) bool {
-		for _, p := range priorities {
-			if strings.Contains(name, strings.ToLower(p)) {
-				return true
-			}
-		}
-		return false
-	}
-	matchesArchive := func(name string, exts []string) bool {
-		for _, ext := range exts {
-			if strings.HasSuffix(name, strings.ToLower(ext)) {
-				return true
-			}
-		}
-		return false
-	}
-
-	var candidates []*github.ReleaseAsset
+	var candidates, filtered []*github.ReleaseAsset
 	for _, asset := range release.Assets {
 		name := strings.ToLower(asset.GetName())
 
 		// DEBUG: Print all asset names and why they are skipped
 		// fmt.Printf("[DEBUG] Checking asset: %s\n", name)
 		PrintDebug(globalCfg, "Checking asset: %s", name)
-		if strings.HasSuffix(name, ".sha256") || strings.HasSuffix(name, ".asc") || strings.HasSuffix(name, ".sig") || strings.HasSuffix(name, ".pem") ||
-			strings.HasSuffix(name, ".md5") || strings.HasSuffix(name, ".sha512") || strings.Contains(name, "checksum") ||
-			strings.HasSuffix(name, ".txt") || strings.HasSuffix(name, ".blockmap") || strings.Contains(name, "source") {
-			PrintDebug(globalCfg, "Skipped (checksum/source): %s", name)
-			continue
-		}
-		if excludeRe != nil && excludeRe.MatchString(name) {
-			PrintDebug(globalCfg, "Skipped (excludeRe): %s", name)
+		if rule := Excluded(rules, name, ownFilterRe); rule != nil {
+			PrintDebug(globalCfg, "Skipped by %s rule %s: %s", rule.Layer, rule.Expr, name)
 			continue
 		}
 		if filterRe != nil && !filterRe.MatchString(name) {
@@ -100,6 +107,15 @@ func FindCompatibleAsset(release *github.RepositoryRelease, repoCfg *config.Repo
 				break
 			}
 		}
+		// The repo's own asset_filter may pick assets the checks below
+		// reject: names without any platform ("tool.tar.gz"), or naming this
+		// OS with an arch spelling they do not accept ("tool_Linux_64bit").
+		// Names of another OS or arch stay rejected.
+		if ownFilterRe != nil && !mentions(name, foreignOS()) && !mentions(name, foreignArch()) &&
+			(isOsMatch || !platformKeyword.MatchString(name)) {
+			filtered = append(filtered, asset)
+		}
+
 		// Strict mode: require both OS and ARCH match
 		if matcherMode == "strict" {
 			if !isOsMatch || !isArchMatch {
@@ -126,8 +142,6 @@ func FindCompatibleAsset(release *github.RepositoryRelease, repoCfg *config.Repo
 			if (strings.Contains(name, "amd64") || strings.Contains(name, "x86_64") || strings.Contains(name, "x64")) && strings.Contains(name, "linux") {
 				// fmt.Printf("[DEBUG] Candidate for Linux AMD64: %s\n", name)
 				PrintDebug(globalCfg, "Candidate for Linux AMD64: %s", name)
-				candidates = append(candidates, asset)
-				// Do NOT continue here, allow further logic to run for other platforms
 			} else {
 				// fmt.Printf("[DEBUG] Skipped (not linux/amd64/x86_64/x64): %s\n", name)
 				PrintDebug(globalCfg, "Skipped (not linux/amd64/x86_64/x64): %s", name)
@@ -147,36 +161,24 @@ func FindCompatibleAsset(release *github.RepositoryRelease, repoCfg *config.Repo
 		candidates = append(candidates, asset)
 	}
 
-	if len(assetPriority) > 0 {
-		for _, asset := range candidates {
-			name := strings.ToLower(asset.GetName())
-			if matchesPriority(name, assetPriority) {
-				if len(preferredArchives) > 0 && matchesArchive(name, preferredArchives) {
-					return asset, nil
-				}
-				if len(preferredArchives) == 0 {
-					return asset, nil
-				}
-			}
-		}
-	}
-
-	if len(preferredArchives) > 0 {
-		for _, asset := range candidates {
-			name := strings.ToLower(asset.GetName())
-			if matchesArchive(name, preferredArchives) {
-				return asset, nil
-			}
-		}
+	if len(candidates) > 0 {
+		best, tied := rankCandidates(candidates, assetPriority, preferredArchives)
+		PrintDebug(globalCfg, "Selected: %s", best.GetName())
+		return best, tied, nil
 	}
 
-	if len(candidates) > 0 {
-		return candidates[0], nil
+	if len(filtered) > 0 {
+		best, _ := rankCandidates(filtered, assetPriority, preferredArchives)
+		PrintDebug(globalCfg, "Selected by asset_filter despite OS/arch checks: %s", best.GetName())
+		return best, nil, nil
 	}
 
 	if matcherMode == "relaxed" && (len(fallbackArch) > 0 || len(fallbackOS) > 0) {
 		for _, asset := range release.Assets {
 			name := strings.ToLower(asset.GetName())
+			if Excluded(rules, name, ownFilterRe) != nil {
+				continue
+			}
 			archOk := len(fallbackArch) == 0
 			osOk := len(fallbackOS) == 0
 			for _, arch := range fallbackArch {
@@ -192,45 +194,102 @@ func FindCompatibleAsset(release *github.RepositoryRelease, repoCfg *config.Repo
 				}
 			}
 			if archOk && osOk {
-				return asset, nil
+				return asset, nil, nil
 			}
 		}
 	}
-	// Enhanced Linux AMD64 matcher: sort candidates by best match
-	if runtime.GOOS == "linux" && runtime.GOARCH == "amd64" && len(candidates) > 1 {
-		preferredOrder := []string{
-			"x86_64-unknown-linux-gnu",
-			"amd64-linux-gnu",
-			"x86_64-linux-gnu",
-			"x86_64-unknown-linux",
-			"amd64-unknown-linux-gnu",
-			"amd64-unknown-linux",
-			"x86_64-linux",
-			"amd64-linux",
-			"x64-linux",
-			"linux-gnu",
-			"musl",
-			"linux",
-		}
-		bestIdx := -1
-		bestScore := -1
-		for idx, asset := range candidates {
-			name := strings.ToLower(asset.GetName())
-			for score, pat := range preferredOrder {
-				if strings.Contains(name, pat) {
-					if bestScore == -1 || score < bestScore {
-						bestScore = score
-						bestIdx = idx
-					}
-				}
+	return nil, nil, fmt.Errorf("no assets found for your OS (%s) and arch (%s)", runtime.GOOS, runtime.GOARCH)
+}
+
+// linuxAMD64Order ranks equally preferred Linux AMD64 candidates: glibc
+// builds first, then musl, then anything else naming linux.
+var linuxAMD64Order = []string{
+	"x86_64-unknown-linux-gnu",
+	"amd64-linux-gnu",
+	"x86_64-linux-gnu",
+	"x86_64-unknown-linux",
+	"amd64-unknown-linux-gnu",
+	"amd64-unknown-linux",
+	"x86_64-linux",
+	"amd64-linux",
+	"x64-linux",
+	"linux-gnu",
+	"musl",
+	"linux",
+}
+
+// rankCandidates picks the best candidate and lists the candidates ranked
+// equal to it, including itself. Assets matching both the asset
+// priority and a preferred archive type come first, then assets of a
+// preferred archive type, then the rest. Within a group, earlier entries of
+// the priority and archive lists win, then the Linux AMD64 order, then the
+// release's own asset order.
+func rankCandidates(candidates []*github.ReleaseAsset, assetPriority, preferredArchives []string) (best *github.ReleaseAsset, tied []*github.ReleaseAsset) {
+	indexOf := func(name string, list []string, match func(name, item string) bool) int {
+		for i, item := range list {
+			if match(name, strings.ToLower(item)) {
+				return i
 			}
 		}
-		if bestIdx >= 0 {
-			return candidates[bestIdx], nil
+		return len(list)
+	}
+	type rank struct{ group, priority, archive, platform int }
+	rankOf := func(asset *github.ReleaseAsset) rank {
+		name := strings.ToLower(asset.GetName())
+		r := rank{
+			priority: indexOf(name, assetPriority, strings.Contains),
+			archive:  indexOf(name, preferredArchives, strings.HasSuffix),
+		}
+		if runtime.GOOS == "linux" && runtime.GOARCH == "amd64" {
+			r.platform = indexOf(name, linuxAMD64Order, strings.Contains)
+		}
+		hasPriority := len(assetPriority) > 0 && r.priority < len(assetPriority)
+		hasArchive := r.archive < len(preferredArchives)
+		switch {
+		case hasPriority && (hasArchive || len(preferredArchives) == 0):
+			r.group = 0
+		case hasArchive:
+			r.group = 1
+		default:
+			r.group = 2
+		}
+		return r
+	}
+	less := func(a, b rank) bool {
+		if a.group != b.group {
+			return a.group < b.group
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		if a.archive != b.archive {
+			return a.archive < b.archive
+		}
+		return a.platform < b.platform
+	}
+
+	best, bestRank := candidates[0], rankOf(candidates[0])
+	for _, asset := range candidates[1:] {
+		if r := rankOf(asset); less(r, bestRank) {
+			best, bestRank = asset, r
+		}
+	}
+	for _, asset := range candidates {
+		if rankOf(asset) == bestRank {
+			tied = append(tied, asset)
 		}
 	}
+	return best, tied
+}
 
-	return nil, fmt.Errorf("no assets found for your OS (%s) and arch (%s)", runtime.GOOS, runtime.GOARCH)
+// mentions reports whether name contains any of words.
+func mentions(name string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(name, w) {
+			return true
+		}
+	}
+	return false
 }
 
 func getSystemKeywords() (os, arch []string) {
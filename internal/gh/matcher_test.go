@@ -0,0 +1,120 @@
+package gh
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/config"
+)
+
+func release(names ...string) *github.RepositoryRelease {
+	r := &github.RepositoryRelease{TagName: github.String("v1.2.3")}
+	for _, name := range names {
+		r.Assets = append(r.Assets, &github.ReleaseAsset{Name: github.String(name)})
+	}
+	return r
+}
+
+func TestFindCompatibleAssetOwnFilter(t *testing.T) {
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skip("asset names below are for linux/amd64")
+	}
+	tests := []struct {
+		name   string
+		assets []string
+		filter string
+		want   string // empty: no asset
+	}{
+		{
+			name:   "current OS with an arch spelling the heuristics reject",
+			assets: []string{"tool_1.2.3_Darwin_64bit.tar.gz", "tool_1.2.3_Linux_64bit.tar.gz", "tool_1.2.3_Linux_arm64.tar.gz"},
+			filter: `^tool_.*_linux_64bit\.tar\.gz$`,
+			want:   "tool_1.2.3_Linux_64bit.tar.gz",
+		},
+		{
+			name:   "no platform in the name",
+			assets: []string{"tool.tar.gz", "checksums.txt"},
+			filter: `^tool\.tar\.gz$`,
+			want:   "tool.tar.gz",
+		},
+		{
+			name:   "another OS stays rejected",
+			assets: []string{"tool_1.2.3_Darwin_64bit.tar.gz"},
+			filter: `darwin`,
+		},
+		{
+			name:   "another arch stays rejected",
+			assets: []string{"tool_1.2.3_Linux_arm64.tar.gz"},
+			filter: `arm64`,
+		},
+		{
+			name:   "without a filter the heuristics reject 64bit",
+			assets: []string{"tool_1.2.3_Linux_64bit.tar.gz"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &config.Repo{AssetFilter: tt.filter}
+			asset, err := FindCompatibleAsset(release(tt.assets...), repo, &config.GlobalConfig{})
+			switch {
+			case tt.want == "" && err == nil:
+				t.Errorf("got %s, want no asset", asset.GetName())
+			case tt.want != "" && err != nil:
+				t.Errorf("got error %v, want %s", err, tt.want)
+			case tt.want != "" && asset.GetName() != tt.want:
+				t.Errorf("got %s, want %s", asset.GetName(), tt.want)
+			}
+		})
+	}
+}
+
+func TestAmbiguousAssets(t *testing.T) {
+	if runtime.GOOS != "linux" || runtime.GOARCH != "amd64" {
+		t.Skip("asset names below are for linux/amd64")
+	}
+	tied := AmbiguousAssets(release("tool-linux-amd64.zip", "tool-linux-amd64.tar.xz"), &config.Repo{}, &config.GlobalConfig{})
+	if len(tied) != 2 {
+		t.Errorf("got %d tied assets, want 2", len(tied))
+	}
+	// The Linux AMD64 order prefers glibc over musl, so this is no tie.
+	tied = AmbiguousAssets(release("tool-x86_64-unknown-linux-musl.tar.gz", "tool-x86_64-unknown-linux-gnu.tar.gz"), &config.Repo{}, &config.GlobalConfig{})
+	if tied != nil {
+		t.Errorf("got %d tied assets, want none", len(tied))
+	}
+	// A repo's own filter is a decision already made.
+	tied = AmbiguousAssets(release("tool-linux-amd64.zip", "tool-linux-amd64.tar.xz"), &config.Repo{AssetFilter: "tool"}, &config.GlobalConfig{})
+	if tied != nil {
+		t.Errorf("got %d tied assets with a filter, want none", len(tied))
+	}
+}
+
+func TestExcludedIgnoresCase(t *testing.T) {
+	rules, err := ExcludeRules(&config.Repo{AssetExclude: "MUSL"}, &config.GlobalConfig{ExcludedPatterns: []string{"Darwin", `\.AppImage$`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		layer string // empty: not excluded
+	}{
+		{"tool_1.2.3_Darwin_64bit.tar.gz", LayerGlobal},
+		{"Tool-1.2.3-x86_64.AppImage", LayerGlobal},
+		{"tool-x86_64-unknown-linux-musl.tar.gz", LayerRepo},
+		{"tool_1.2.3_checksums.txt", LayerBuiltin},
+		{"tool_1.2.3_Linux_64bit.tar.gz", ""},
+	}
+	for _, tt := range tests {
+		rule := Excluded(rules, strings.ToLower(tt.name), nil)
+		switch {
+		case tt.layer == "" && rule != nil:
+			t.Errorf("%s excluded by %s rule %s", tt.name, rule.Layer, rule.Expr)
+		case tt.layer != "" && (rule == nil || rule.Layer != tt.layer):
+			t.Errorf("%s: got rule %+v, want one from %s", tt.name, rule, tt.layer)
+		}
+	}
+	if _, err := ExcludeRules(&config.Repo{}, &config.GlobalConfig{ExcludedPatterns: []string{"(unclosed"}}); err == nil {
+		t.Error("ExcludeRules accepted an invalid pattern")
+	}
+}
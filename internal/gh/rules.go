@@ -0,0 +1,79 @@
+package gh
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/user/track/internal/config"
+)
+
+// Exclusion rule layers, in the order they are checked.
+const (
+	LayerBuiltin = "built-in"
+	LayerGlobal  = "global excluded_patterns"
+	LayerRepo    = "repo asset_exclude"
+)
+
+// builtinExcludes skip files that are never the tool itself: checksums,
+// signatures, certificates, notes and source archives.
+var builtinExcludes = []string{
+	`\.(sha1|sha256|sha512|md5|asc|sig|pem|txt|blockmap)$`,
+	`checksum`,
+	`source`,
+}
+
+// ExcludeRule skips assets whose name matches Pattern, ignoring case.
+// Expr is the pattern as configured.
+type ExcludeRule struct {
+	Layer   string
+	Expr    string
+	Pattern *regexp.Regexp
+}
+
+// ExcludeRules builds the layered exclusion rules for a repo: built-in
+// defaults, then the global excluded_patterns, then the repo's asset_exclude.
+// An invalid pattern is an error naming its layer.
+func ExcludeRules(repoCfg *config.Repo, globalCfg *config.GlobalConfig) ([]ExcludeRule, error) {
+	var rules []ExcludeRule
+	add := func(layer, pattern string) error {
+		// Asset names are lower-cased before matching, so user patterns
+		// like "Darwin" or "\.AppImage$" must ignore case to match at all.
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return fmt.Errorf("invalid %s pattern %q: %w", layer, pattern, err)
+		}
+		rules = append(rules, ExcludeRule{Layer: layer, Expr: pattern, Pattern: re})
+		return nil
+	}
+	for _, p := range builtinExcludes {
+		add(LayerBuiltin, p)
+	}
+	if globalCfg != nil {
+		for _, p := range globalCfg.ExcludedPatterns {
+			if err := add(LayerGlobal, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if repoCfg.AssetExclude != "" {
+		if err := add(LayerRepo, repoCfg.AssetExclude); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// Excluded returns the first rule that matches the lower-cased asset name.
+// Built-in rules are skipped for assets the repo's own asset_filter selects,
+// so an explicit filter can still pick e.g. a .txt or "source" asset.
+func Excluded(rules []ExcludeRule, name string, filterRe *regexp.Regexp) *ExcludeRule {
+	for i, r := range rules {
+		if r.Layer == LayerBuiltin && filterRe != nil && filterRe.MatchString(name) {
+			continue
+		}
+		if r.Pattern.MatchString(name) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package gh
+
+import (
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/config"
+)
+
+// ScoredAsset is a release asset rated for how well it fits this platform.
+type ScoredAsset struct {
+	Asset *github.ReleaseAsset
+	Score int
+	Notes []string // what the score is made of, e.g. "os", "arch"
+}
+
+// ScoreAssets rates every asset not excluded by the exclusion rules: points
+// for naming this OS and architecture, for the asset priority and for a
+// preferred archive type. The result is sorted best first; hidden is the
+// number of excluded assets left out.
+func ScoreAssets(release *github.RepositoryRelease, repoCfg *config.Repo, globalCfg *config.GlobalConfig) (scored []ScoredAsset, hidden int) {
+	rules, _ := ExcludeRules(repoCfg, globalCfg)
+	osChecks, archChecks := getSystemKeywords()
+	assetPriority := repoCfg.AssetPriority
+	preferredArchives := repoCfg.PreferredArchives
+	if globalCfg != nil {
+		if len(assetPriority) == 0 {
+			assetPriority = globalCfg.DefaultAssetPriority
+		}
+		if len(preferredArchives) == 0 {
+			preferredArchives = globalCfg.PreferredArchiveTypes
+		}
+	}
+	containsAny := func(name string, words []string, match func(string, string) bool) bool {
+		for _, w := range words {
+			if match(name, strings.ToLower(w)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, asset := range release.Assets {
+		name := strings.ToLower(asset.GetName())
+		if Excluded(rules, name, nil) != nil {
+			hidden++
+			continue
+		}
+		s := ScoredAsset{Asset: asset}
+		add := func(points int, note string) {
+			s.Score += points
+			s.Notes = append(s.Notes, note)
+		}
+		if strings.Contains(name, runtime.GOOS) {
+			add(4, "os")
+		} else if containsAny(name, osChecks, strings.Contains) {
+			add(2, "os?")
+		}
+		if containsAny(name, foreignOS(), strings.Contains) {
+			add(-4, "other os")
+		}
+		if containsAny(name, foreignArch(), strings.Contains) {
+			add(-4, "other arch")
+		} else if containsAny(name, archChecks, strings.Contains) {
+			add(3, "arch")
+		}
+		if containsAny(name, assetPriority, strings.Contains) {
+			add(2, "priority")
+		}
+		if containsAny(name, preferredArchives, strings.HasSuffix) {
+			add(1, "archive")
+		}
+		scored = append(scored, s)
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	return scored, hidden
+}
+
+// foreignOS lists names of other operating systems than this one.
+func foreignOS() []string {
+	var names []string
+	for goos, words := range map[string][]string{
+		"linux":   {"linux"},
+		"darwin":  {"darwin", "macos", "osx"},
+		"windows": {"windows", "win64", "win32", ".exe", ".msi"},
+		"freebsd": {"freebsd"},
+	} {
+		if goos != runtime.GOOS {
+			names = append(names, words...)
+		}
+	}
+	return names
+}
+
+// foreignArch lists names of other architectures than this one.
+func foreignArch() []string {
+	switch runtime.GOARCH {
+	case "amd64":
+		return []string{"arm64", "aarch64", "armv", "386", "i686", "ppc64", "s390x", "riscv"}
+	case "arm64":
+		return []string{"x86_64", "amd64", "x64", "386", "i686", "armv7", "ppc64", "s390x", "riscv"}
+	}
+	return nil
+}
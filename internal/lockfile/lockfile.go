@@ -0,0 +1,96 @@
+package lockfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/fsutil"
+)
+
+// FileName is the default lockfile name, stored next to config.json.
+const FileName = "track.lock"
+
+// formatVersion is bumped when the lockfile layout changes incompatibly.
+// Version 2 added the repo settings, which older releases would drop.
+const formatVersion = 2
+
+// Lockfile pins the exact release asset of every tool, per platform.
+type Lockfile struct {
+	Version int               `json:"version"`
+	Repos   map[string]*Entry `json:"repos"`
+}
+
+// Entry pins one repository to a tag.
+type Entry struct {
+	Tag         string            `json:"tag"`
+	InstallName string            `json:"install_name,omitempty"`
+	Assets      map[string]*Asset `json:"assets"` // keyed by Platform()
+
+	// Repo holds the repo's settings in the export format (source,
+	// url_template, asset_filter, build, ...), so that sync can track it on
+	// a machine where it is missing. Lockfiles of version 1 have none.
+	Repo json.RawMessage `json:"repo,omitempty"`
+}
+
+// Asset is the exact file installed on one platform.
+type Asset struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// Platform is the key used for the current OS and architecture.
+func Platform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// DefaultPath is track.lock in the config directory.
+func DefaultPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads a lockfile. A missing file yields an empty lockfile.
+func Load(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Lockfile{Version: formatVersion, Repos: make(map[string]*Entry)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	var l Lockfile
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %w", path, err)
+	}
+	if l.Version > formatVersion {
+		return nil, fmt.Errorf("lockfile %s has version %d, this track supports up to %d", path, l.Version, formatVersion)
+	}
+	if l.Repos == nil {
+		l.Repos = make(map[string]*Entry)
+	}
+	return &l, nil
+}
+
+// Save writes the lockfile.
+func (l *Lockfile) Save(path string) error {
+	l.Version = formatVersion
+	// Regexes and URL templates in repo settings stay readable without
+	// HTML escaping of <, > and &.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(l); err != nil {
+		return fmt.Errorf("failed to marshal lockfile: %w", err)
+	}
+	return fsutil.WriteFileAtomic(path, buf.Bytes(), 0644)
+}
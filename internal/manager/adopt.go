@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/adopt"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/fsutil"
+)
+
+// AdoptedDir holds unmanaged binaries that 'track adopt' moved aside.
+func (m *Manager) AdoptedDir() string {
+	return filepath.Join(m.Cfg.Global.DataDir, "adopted")
+}
+
+// ReleaseForVersion finds the release of a version reported by a binary,
+// trying the tag with and without a "v" prefix.
+func (m *Manager) ReleaseForVersion(repoPath string, repoCfg *config.Repo, version string) (*github.RepositoryRelease, error) {
+	var firstErr error
+	for _, tag := range []string{"v" + version, version} {
+		release, err := m.ReleaseByTag(repoPath, repoCfg, tag)
+		if err == nil {
+			return release, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, fmt.Errorf("no release tagged v%s or %s: %w", version, version, firstErr)
+}
+
+// AdoptRepo returns the config for tracking c, linked under the binary's
+// own name.
+func (m *Manager) AdoptRepo(c adopt.Candidate) *config.Repo {
+	repoCfg := &config.Repo{}
+	if m.InstallName(c.Repo, repoCfg) != c.Name {
+		repoCfg.InstallName = c.Name
+	}
+	return repoCfg
+}
+
+// Adopt tracks the repo of the unmanaged binary c and installs release.
+// A binary sitting where track creates its links would be overwritten, so
+// it is always moved to AdoptedDir first; with replace, c.Path is replaced
+// by a link to the managed install wherever it is. If anything fails, the
+// original binary is put back. It returns where the original was moved.
+func (m *Manager) Adopt(c adopt.Candidate, repoCfg *config.Repo, release *github.RepositoryRelease, replace bool) (string, error) {
+	if replace && runtime.GOOS == "windows" {
+		return "", fmt.Errorf("replacing binaries with links is not supported on Windows")
+	}
+	links := m.linkPaths(m.InstallName(c.Repo, repoCfg))
+	atLink := false
+	for _, l := range links {
+		if l == c.Path {
+			atLink = true
+		}
+	}
+
+	var backup string
+	if replace || atLink {
+		version := c.Version
+		if version == "" {
+			version = "unknown"
+		}
+		backup = filepath.Join(m.AdoptedDir(), c.Name+"@"+version)
+		if err := os.MkdirAll(m.AdoptedDir(), 0755); err != nil {
+			return "", err
+		}
+		if err := fsutil.Move(c.Path, backup); err != nil {
+			return "", fmt.Errorf("cannot move %s aside: %w", c.Path, err)
+		}
+	}
+	restore := func() {
+		if backup != "" {
+			os.Remove(c.Path)
+			fsutil.Move(backup, c.Path)
+		}
+	}
+
+	if err := m.AddAndInstall(c.Repo, repoCfg, release); err != nil {
+		restore()
+		return "", err
+	}
+	if replace && !atLink {
+		if err := os.Symlink(links[0], c.Path); err != nil {
+			restore()
+			return "", fmt.Errorf("installed, but linking %s failed and the original was put back: %w", c.Path, err)
+		}
+		fmt.Printf("Replaced %s with a link to %s\n", c.Path, links[0])
+	}
+	return backup, nil
+}
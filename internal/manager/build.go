@@ -0,0 +1,102 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/archiver"
+	"github.com/user/track/internal/builder"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/downloader"
+	"github.com/user/track/internal/source"
+)
+
+// buildFromSource downloads the source tarball for release, builds it with the
+// local toolchain and places the binary in versionDir.
+func (m *Manager) buildFromSource(repoPath string, repoCfg *config.Repo, release *github.RepositoryRelease, versionDir string) (string, error) {
+	version := release.GetTagName()
+	_, name, _ := strings.Cut(repoPath, "/")
+	installName := m.InstallName(repoPath, repoCfg)
+
+	tarballURL, err := sourceTarballURL(repoPath, repoCfg, release)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", fmt.Errorf("could not create version directory: %w", err)
+	}
+	tarballPath := filepath.Join(versionDir, fmt.Sprintf("%s-%s-src.tar.gz", name, version))
+	fmt.Printf("Downloading source %s...\n", tarballURL)
+	if err := downloader.DownloadFile(tarballURL, tarballPath); err != nil {
+		return "", fmt.Errorf("failed to download source: %w", err)
+	}
+
+	srcDir := filepath.Join(versionDir, ".src")
+	defer os.RemoveAll(srcDir)
+	if err := archiver.Extract(tarballPath, srcDir); err != nil {
+		return "", fmt.Errorf("failed to extract source: %w", err)
+	}
+
+	root, toolchain, err := builder.FindRoot(srcDir)
+	if err != nil {
+		return "", fmt.Errorf("cannot build %s from source: %w", repoPath, err)
+	}
+	built, err := builder.Build(root, toolchain, builder.Options{
+		Name:    installName,
+		Command: repoCfg.Build.Command,
+		Output:  repoCfg.Build.Output,
+		Env:     repoCfg.Build.Env,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build %s %s: %w", repoPath, version, err)
+	}
+
+	target := filepath.Join(versionDir, installName)
+	if runtime.GOOS == "windows" {
+		target += ".exe"
+	}
+	if err := copyFile(built, target, 0755); err != nil {
+		return "", fmt.Errorf("failed to place built binary: %w", err)
+	}
+	os.RemoveAll(srcDir) // not part of the install
+	if err := writeManifest(repoPath, version, versionDir, tarballPath, tarballURL); err != nil {
+		return "", err
+	}
+	fmt.Printf("Built %s from source.\n", target)
+	return target, nil
+}
+
+// sourceTarballURL is the build's source_url template, the release's tarball,
+// or the GitHub archive URL for the tag.
+func sourceTarballURL(repoPath string, repoCfg *config.Repo, release *github.RepositoryRelease) (string, error) {
+	if repoCfg.Build.SourceURL != "" {
+		return source.Render(repoCfg.Build.SourceURL, release.GetTagName())
+	}
+	if release.GetTarballURL() != "" {
+		return release.GetTarballURL(), nil
+	}
+	return fmt.Sprintf("https://github.com/%s/archive/refs/tags/%s.tar.gz", repoPath, release.GetTagName()), nil
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,341 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/user/track/internal/config"
+)
+
+// Finding is one problem found by Diagnose.
+type Finding struct {
+	Problem string
+	Warning bool   // true for problems track works around, e.g. a missing token
+	Hint    string // what the user can do about it, if it cannot be fixed
+	FixDesc string // what Fix does, if it can be fixed
+
+	fix func() error
+}
+
+// Fixable reports whether Fix can repair the problem.
+func (f Finding) Fixable() bool {
+	return f.fix != nil
+}
+
+// Fix repairs the problem.
+func (f Finding) Fix() error {
+	if f.fix == nil {
+		return fmt.Errorf("cannot be fixed automatically")
+	}
+	return f.fix()
+}
+
+// Check is one area Diagnose looks at and what it found.
+type Check struct {
+	Name     string
+	Findings []Finding
+}
+
+// Diagnose audits the config, the data directory, installs, links and PATH.
+func (m *Manager) Diagnose() []Check {
+	return []Check{
+		{"config", m.checkConfig()},
+		{"github token", checkToken()},
+		{"data dir", m.checkDataDir()},
+		{"PATH", m.checkPath()},
+		{"installs", m.checkInstalls()},
+		{"links", m.checkLinks()},
+		{"shadowing", m.checkShadowing()},
+	}
+}
+
+func (m *Manager) checkConfig() []Finding {
+	path, err := config.Path()
+	if err != nil {
+		return []Finding{{Problem: err.Error()}}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []Finding{{Problem: err.Error()}}
+	}
+	issues, err := config.Check(data)
+	if err != nil {
+		return []Finding{{Problem: fmt.Sprintf("%s: %v", path, err), Hint: "fix the JSON by hand"}}
+	}
+	var findings []Finding
+	for _, issue := range issues {
+		findings = append(findings, Finding{
+			Problem: fmt.Sprintf("%s: %s", issue.Field, issue.Message),
+			Warning: issue.Warning,
+			Hint:    "see 'track config validate'",
+		})
+	}
+	return findings
+}
+
+func checkToken() []Finding {
+	if os.Getenv("GITHUB_TOKEN") != "" {
+		return nil
+	}
+	return []Finding{{
+		Problem: "GITHUB_TOKEN is not set: GitHub allows 60 API requests per hour and private repositories cannot be read",
+		Warning: true,
+		Hint:    "export GITHUB_TOKEN=<token>, e.g. from 'gh auth token'",
+	}}
+}
+
+func (m *Manager) checkDataDir() []Finding {
+	dir := m.Cfg.Global.DataDir
+	fi, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return []Finding{{
+			Problem: fmt.Sprintf("data_dir %s does not exist", dir),
+			FixDesc: "create it",
+			fix:     func() error { return os.MkdirAll(dir, 0755) },
+		}}
+	}
+	if err != nil {
+		return []Finding{{Problem: err.Error()}}
+	}
+	if !fi.IsDir() {
+		return []Finding{{Problem: fmt.Sprintf("data_dir %s is not a directory", dir), Hint: "use 'track migrate-data' to choose another"}}
+	}
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return []Finding{{Problem: fmt.Sprintf("data_dir %s is not writable: %v", dir, err)}}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return nil
+}
+
+// linkDir is the directory that must be on PATH for track's links to work.
+func (m *Manager) linkDir() string {
+	paths := m.linkPaths("x")
+	return filepath.Dir(paths[len(paths)-1])
+}
+
+func (m *Manager) checkPath() []Finding {
+	dir := m.linkDir()
+	if onPath(dir) {
+		return nil
+	}
+	hint := fmt.Sprintf("add it to your shell profile: export PATH=\"%s:$PATH\"", dir)
+	if runtime.GOOS == "windows" {
+		hint = fmt.Sprintf("add %s to the user PATH in System Properties > Environment Variables", dir)
+	}
+	return []Finding{{Problem: fmt.Sprintf("%s is not on PATH, so installed tools cannot be run by name", dir), Hint: hint}}
+}
+
+func onPath(dir string) bool {
+	want := resolvePath(dir)
+	for _, d := range filepath.SplitList(os.Getenv("PATH")) {
+		if d != "" && resolvePath(d) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath cleans path and resolves symlinks where possible, for comparing.
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
+func (m *Manager) checkInstalls() []Finding {
+	var findings []Finding
+	for _, repoPath := range m.Cfg.SortedRepos() {
+		repoCfg := m.Cfg.Repos[repoPath]
+		version := repoCfg.CurrentVersion
+		if version == "" {
+			continue
+		}
+		reinstall := func() error {
+			release, err := m.ReleaseByTag(repoPath, repoCfg, version)
+			if err != nil {
+				return err
+			}
+			return m.InstallVersion(repoPath, release)
+		}
+		versionDir := m.VersionDir(repoPath, version)
+		if _, err := os.Stat(versionDir); err != nil {
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("%s: current_version %s is not installed (%s is missing)", repoPath, version, versionDir),
+				FixDesc: fmt.Sprintf("reinstall %s", version),
+				fix:     reinstall,
+			})
+			continue
+		}
+		if _, err := m.findExecutable(repoPath, repoCfg, versionDir); err == nil {
+			continue
+		}
+		// FindExecutable skips files without the executable bit, so look at
+		// the file the links were made for.
+		if target := m.linkTarget(repoPath, repoCfg); target != "" && within(target, versionDir) {
+			if fi, err := os.Stat(target); err == nil && runtime.GOOS != "windows" && fi.Mode()&0111 == 0 {
+				findings = append(findings, Finding{
+					Problem: fmt.Sprintf("%s %s: %s is not executable", repoPath, version, target),
+					FixDesc: "make it executable",
+					fix:     func() error { return os.Chmod(target, fi.Mode()|0755) },
+				})
+				continue
+			}
+		}
+		findings = append(findings, Finding{
+			Problem: fmt.Sprintf("%s %s: no executable in %s", repoPath, version, versionDir),
+			FixDesc: fmt.Sprintf("reinstall %s, replacing the folder once the new download passes its smoke test", version),
+			fix:     reinstall,
+		})
+	}
+	return findings
+}
+
+// linkTarget returns the file the repo's latest-folder link points to, or "".
+func (m *Manager) linkTarget(repoPath string, repoCfg *config.Repo) string {
+	target, err := os.Readlink(m.linkPaths(m.InstallName(repoPath, repoCfg))[0])
+	if err != nil {
+		return ""
+	}
+	return target
+}
+
+func (m *Manager) checkLinks() []Finding {
+	var findings []Finding
+	for _, repoPath := range m.Cfg.SortedRepos() {
+		repoCfg := m.Cfg.Repos[repoPath]
+		if repoCfg.CurrentVersion == "" {
+			continue
+		}
+		exe, err := m.installedExecutable(repoPath, repoCfg, repoCfg.CurrentVersion)
+		if err != nil {
+			continue // reported by checkInstalls
+		}
+		installName := m.InstallName(repoPath, repoCfg)
+		var problem string
+		for _, link := range m.linkPaths(installName) {
+			if isForeignFile(link, m.Cfg.Global.ShimMode) {
+				// Relinking would delete a binary the user put there.
+				problem = ""
+				findings = append(findings, Finding{
+					Problem: fmt.Sprintf("%s: %s is a regular file, not a link managed by track", repoPath, link),
+					Hint:    fmt.Sprintf("move it away (mv %s %s.bak), then run 'track doctor --fix' to link the managed version", link, link),
+				})
+				break
+			}
+			if problem == "" {
+				problem = linkProblem(link, exe, m.Cfg.Global.ShimMode)
+			}
+		}
+		if problem != "" {
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("%s: %s", repoPath, problem),
+				FixDesc: "recreate the links",
+				fix:     func() error { return m.Relink(repoPath) },
+			})
+		}
+	}
+
+	// Links left behind by removed repos or deleted version directories.
+	for _, dir := range []string{filepath.Join(m.Cfg.Global.DataDir, "latest"), m.linkDir()} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			if entry.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			target, err := os.Readlink(path)
+			if err != nil || !within(target, m.Cfg.Global.DataDir) {
+				continue
+			}
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				findings = append(findings, Finding{
+					Problem: fmt.Sprintf("dangling link %s -> %s", path, target),
+					FixDesc: "remove it",
+					fix: func() error {
+						// An earlier fix may have reinstalled what it points to.
+						if _, err := os.Stat(path); err == nil {
+							return nil
+						}
+						return os.Remove(path)
+					},
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// isForeignFile reports whether link is a regular file where track expects
+// a symlink, i.e. something track did not create.
+func isForeignFile(link string, shimMode bool) bool {
+	if shimMode || runtime.GOOS == "windows" {
+		return false
+	}
+	fi, err := os.Lstat(link)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+// linkProblem describes what is wrong with a link that should run exe, or
+// returns "".
+func linkProblem(link, exe string, shimMode bool) string {
+	fi, err := os.Lstat(link)
+	if err != nil {
+		return fmt.Sprintf("%s is missing", link)
+	}
+	if shimMode || runtime.GOOS == "windows" {
+		if fi.Mode().IsRegular() {
+			return ""
+		}
+		return fmt.Sprintf("%s is not a shim", link)
+	}
+	if fi.Mode()&os.ModeSymlink == 0 {
+		return fmt.Sprintf("%s is a regular file, not a link managed by track", link)
+	}
+	target, _ := os.Readlink(link)
+	if _, err := os.Stat(link); err != nil {
+		return fmt.Sprintf("%s is dangling (-> %s)", link, target)
+	}
+	if resolvePath(link) != resolvePath(exe) {
+		return fmt.Sprintf("%s points to %s instead of %s", link, target, exe)
+	}
+	return ""
+}
+
+func (m *Manager) checkShadowing() []Finding {
+	var findings []Finding
+	for _, repoPath := range m.Cfg.SortedRepos() {
+		repoCfg := m.Cfg.Repos[repoPath]
+		if repoCfg.CurrentVersion == "" {
+			continue
+		}
+		name := m.InstallName(repoPath, repoCfg)
+		found, err := exec.LookPath(name)
+		if err != nil {
+			continue // not on PATH at all; reported by checkPath or checkLinks
+		}
+		found, _ = filepath.Abs(found)
+		ours := false
+		for _, link := range m.linkPaths(name) {
+			if resolvePath(filepath.Dir(found)) == resolvePath(filepath.Dir(link)) {
+				ours = true
+			}
+		}
+		if !ours {
+			findings = append(findings, Finding{
+				Problem: fmt.Sprintf("%s: running '%s' starts %s, not the version track manages", repoPath, name, found),
+				Warning: true,
+				Hint:    fmt.Sprintf("remove or rename %s, or move %s earlier in PATH", found, m.linkDir()),
+			})
+		}
+	}
+	return findings
+}
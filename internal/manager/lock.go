@@ -0,0 +1,98 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/downloader"
+	"github.com/user/track/internal/lockfile"
+)
+
+// LockAsset resolves the asset of release for this platform together with its
+// sha256. The archive already kept in the version directory is hashed when
+// present; otherwise the asset is downloaded to a temporary file.
+func (m *Manager) LockAsset(repoPath string, repoCfg *config.Repo, release *github.RepositoryRelease) (*lockfile.Asset, error) {
+	if repoCfg.Build != nil && repoCfg.Build.Always {
+		return nil, fmt.Errorf("%s is built from source and cannot be locked", repoPath)
+	}
+	asset, err := m.SelectAsset(repoCfg, release)
+	if err != nil {
+		return nil, fmt.Errorf("could not find compatible asset for %s in version %s: %w", repoPath, release.GetTagName(), err)
+	}
+	locked := &lockfile.Asset{Name: asset.GetName(), URL: asset.GetBrowserDownloadURL()}
+
+	local := filepath.Join(m.VersionDir(repoPath, release.GetTagName()), asset.GetName())
+	if sum, err := downloader.SHA256(local); err == nil {
+		locked.SHA256 = sum
+		return locked, nil
+	}
+
+	tmp, err := os.CreateTemp("", "track-lock-*")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	fmt.Printf("Downloading %s to compute its digest...\n", locked.URL)
+	if err := downloader.DownloadFile(locked.URL, tmp.Name()); err != nil {
+		return nil, fmt.Errorf("failed to download asset: %w", err)
+	}
+	if locked.SHA256, err = downloader.SHA256(tmp.Name()); err != nil {
+		return nil, err
+	}
+	return locked, nil
+}
+
+// SyncLocked installs exactly the asset locked for this platform, verifying
+// its digest, and makes it the current version. It reports whether anything
+// had to be installed.
+func (m *Manager) SyncLocked(repoPath string, entry *lockfile.Entry) (bool, error) {
+	repoCfg, ok := m.Cfg.Repos[repoPath]
+	if !ok {
+		return false, fmt.Errorf("repository '%s' not tracked", repoPath)
+	}
+	asset, ok := entry.Assets[lockfile.Platform()]
+	if !ok {
+		return false, fmt.Errorf("no asset locked for %s; run 'track lock' on this platform", lockfile.Platform())
+	}
+
+	versionDir := m.VersionDir(repoPath, entry.Tag)
+	if repoCfg.CurrentVersion == entry.Tag && m.isLinked(m.InstallName(repoPath, repoCfg)) {
+		sum, err := downloader.SHA256(filepath.Join(versionDir, asset.Name))
+		if err == nil && strings.EqualFold(sum, asset.SHA256) {
+			return false, nil
+		}
+	}
+
+	stage, err := m.stageDir(repoPath, entry.Tag)
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(stage)
+	executablePath, err := m.fetchAsset(repoPath, repoCfg, entry.Tag, asset.Name, asset.URL, asset.SHA256, stage)
+	if err != nil {
+		return false, err
+	}
+	if err := m.smokeTest(repoCfg, entry.Tag, executablePath); err != nil {
+		return false, m.rejectVersion(repoPath, repoCfg, entry.Tag, err)
+	}
+	if executablePath, err = m.commitStage(repoPath, entry.Tag, stage, executablePath); err != nil {
+		return false, err
+	}
+	m.linkExecutable(m.InstallName(repoPath, repoCfg), executablePath)
+	if m.Cfg.Global.LinkVersions {
+		m.linkVersioned(m.InstallName(repoPath, repoCfg), entry.Tag, executablePath)
+	}
+
+	repoCfg.CurrentVersion = entry.Tag
+	repoCfg.LastFailure = nil
+	if err := m.Cfg.Save(); err != nil {
+		return false, fmt.Errorf("failed to save config after sync: %w", err)
+	}
+	fmt.Printf("Successfully installed %s version %s.\n", repoPath, entry.Tag)
+	return true, nil
+}
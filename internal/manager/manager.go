@@ -1,7 +1,6 @@
 package manager
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,11 +11,15 @@ import (
 	"github.com/user/track/internal/archiver"
 	"github.com/user/track/internal/config"
 	"github.com/user/track/internal/downloader"
-	"github.com/user/track/internal/gh"
+	"github.com/user/track/internal/lockfile"
 )
 
 type Manager struct {
 	Cfg *config.Config
+
+	// PickAsset, if set, is asked to choose an asset when matching fails.
+	PickAsset AssetPicker
+	picked    map[string]*github.ReleaseAsset // PickAsset's answers by repo@tag
 }
 
 func New() (*Manager, error) {
@@ -51,35 +54,14 @@ func (m *Manager) UpdateRepo(repoPath string, force bool) error {
 		return fmt.Errorf("repository '%s' not tracked", repoPath)
 	}
 
-	owner, name, _ := strings.Cut(repoPath, "/")
-	client := gh.NewClient(context.Background(), "")
-
-	latestRelease, err := client.GetLatestRelease(context.Background(), owner, name, repoCfg.IncludePrerelease)
+	latestRelease, err := m.LatestRelease(repoPath, repoCfg)
 	if err != nil {
 		return fmt.Errorf("failed to get latest release for %s: %w", repoPath, err)
 	}
 
 	latestVersion := latestRelease.GetTagName()
 
-	installName := repoCfg.InstallName
-	if installName == "" {
-		installName = name
-	}
-	latestDir := filepath.Join(m.Cfg.Global.DataDir, "latest")
-	var binaryExists bool
-	if runtime.GOOS == "windows" {
-		shimPath := filepath.Join(latestDir, installName+".cmd")
-		if fi, err := os.Stat(shimPath); err == nil && !fi.IsDir() {
-			binaryExists = true
-		}
-	} else {
-		binPath := filepath.Join(latestDir, installName)
-		if fi, err := os.Stat(binPath); err == nil && !fi.IsDir() {
-			binaryExists = true
-		}
-	}
-
-	if !force && latestVersion == repoCfg.CurrentVersion && binaryExists {
+	if !force && latestVersion == repoCfg.CurrentVersion && m.isLinked(m.InstallName(repoPath, repoCfg)) {
 		fmt.Printf("'%s' is already up-to-date (version %s).\n", repoPath, latestVersion)
 		return nil
 	}
@@ -99,40 +81,117 @@ func (m *Manager) UpdateRepo(repoPath string, force bool) error {
 func (m *Manager) InstallVersion(repoPath string, release *github.RepositoryRelease) error {
 	repoCfg := m.Cfg.Repos[repoPath]
 	version := release.GetTagName()
+
+	stage, err := m.stageDir(repoPath, version)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(stage)
+	executablePath, err := m.fetch(repoPath, repoCfg, release, stage)
+	if err != nil {
+		return err
+	}
+	if err := m.smokeTest(repoCfg, version, executablePath); err != nil {
+		return m.rejectVersion(repoPath, repoCfg, version, err)
+	}
+	if executablePath, err = m.commitStage(repoPath, version, stage, executablePath); err != nil {
+		return err
+	}
+
+	m.linkExecutable(m.InstallName(repoPath, repoCfg), executablePath)
+	if m.Cfg.Global.LinkVersions {
+		m.linkVersioned(m.InstallName(repoPath, repoCfg), version, executablePath)
+	}
+
+	repoCfg.CurrentVersion = version
+	repoCfg.LastFailure = nil
+	if err := m.Cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config after update: %w", err)
+	}
+
+	fmt.Printf("Successfully installed %s version %s.\n", repoPath, version)
+	return nil
+}
+
+// VersionDir is where a given version of a repo is installed.
+func (m *Manager) VersionDir(repoPath, version string) string {
 	_, name, _ := strings.Cut(repoPath, "/")
+	return filepath.Join(m.Cfg.Global.DataDir, name, "general", version)
+}
 
-	asset, err := gh.FindCompatibleAsset(release, repoCfg, &m.Cfg.Global)
+// InstallName is the name the repo's executable is linked as: the repo's
+// install_name, else the global default_install_name template rendered with
+// {{.Owner}} and {{.Name}}, else the repo name.
+func (m *Manager) InstallName(repoPath string, repoCfg *config.Repo) string {
+	return m.Cfg.Global.InstallName(repoPath, repoCfg)
+}
+
+// fetch downloads and unpacks release into versionDir, building from source
+// when no asset fits and the repo opted in, and returns the executable path.
+func (m *Manager) fetch(repoPath string, repoCfg *config.Repo, release *github.RepositoryRelease, versionDir string) (string, error) {
+	version := release.GetTagName()
+
+	if repoCfg.Build != nil && repoCfg.Build.Always {
+		return m.buildFromSource(repoPath, repoCfg, release, versionDir)
+	}
+
+	asset, err := m.SelectAsset(repoCfg, release)
 	if err != nil {
-		return fmt.Errorf("could not find compatible asset for %s in version %s: %w", repoPath, version, err)
+		if repoCfg.Build == nil {
+			return "", fmt.Errorf("could not find compatible asset for %s in version %s: %w", repoPath, version, err)
+		}
+		fmt.Printf("No compatible asset for %s in version %s (%v), building from source.\n", repoPath, version, err)
+		return m.buildFromSource(repoPath, repoCfg, release, versionDir)
 	}
 	fmt.Printf("Found compatible asset: %s\n", asset.GetName())
+	return m.fetchAsset(repoPath, repoCfg, version, asset.GetName(), asset.GetBrowserDownloadURL(), "", versionDir)
+}
 
-	repoDir := filepath.Join(m.Cfg.Global.DataDir, name)
-	versionDir := filepath.Join(repoDir, "general", version)
-	archivePath := filepath.Join(versionDir, asset.GetName())
+// fetchAsset downloads a known asset of release tag into versionDir, unpacks
+// it and records the result in the version's manifest. If wantSHA256 is set
+// the download must match it.
+func (m *Manager) fetchAsset(repoPath string, repoCfg *config.Repo, tag, assetName, url, wantSHA256, versionDir string) (string, error) {
+	_, name, _ := strings.Cut(repoPath, "/")
+	installName := m.InstallName(repoPath, repoCfg)
+	archivePath := filepath.Join(versionDir, assetName)
 
 	if err := os.MkdirAll(versionDir, 0755); err != nil {
-		return fmt.Errorf("could not create version directory: %w", err)
+		return "", fmt.Errorf("could not create version directory: %w", err)
 	}
 
-	fmt.Printf("Downloading %s...\n", asset.GetBrowserDownloadURL())
-	if err := downloader.DownloadFile(asset.GetBrowserDownloadURL(), archivePath); err != nil {
-		return fmt.Errorf("failed to download asset: %w", err)
+	fmt.Printf("Downloading %s...\n", url)
+	if err := downloader.DownloadFile(url, archivePath); err != nil {
+		return "", fmt.Errorf("failed to download asset: %w", err)
 	}
 
-	fmt.Printf("Extracting %s...\n", asset.GetName())
-	if err := archiver.Extract(archivePath, versionDir); err != nil {
-		return fmt.Errorf("failed to extract archive: %w", err)
+	if wantSHA256 != "" {
+		got, err := downloader.SHA256(archivePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash %s: %w", assetName, err)
+		}
+		if !strings.EqualFold(got, wantSHA256) {
+			os.Remove(archivePath)
+			return "", fmt.Errorf("digest mismatch for %s: expected sha256 %s, got %s", assetName, wantSHA256, got)
+		}
+		fmt.Printf("Verified sha256 of %s.\n", assetName)
 	}
 
-	installName := repoCfg.InstallName
-	if installName == "" {
-		installName = name
+	if archiver.IsArchive(archivePath) {
+		fmt.Printf("Extracting %s...\n", assetName)
+		if err := archiver.Extract(archivePath, versionDir); err != nil {
+			return "", fmt.Errorf("failed to extract archive: %w", err)
+		}
+	} else if err := os.Chmod(archivePath, 0755); err != nil {
+		// Plain binary downloads (common for url sources) are used as-is.
+		return "", fmt.Errorf("failed to make %s executable: %w", assetName, err)
 	}
 
 	executablePath, err := archiver.FindExecutable(versionDir, name, installName)
 	if err != nil {
-		return fmt.Errorf("could not find executable in archive for %s: %w", repoPath, err)
+		return "", fmt.Errorf("could not find executable in archive for %s: %w", repoPath, err)
+	}
+	if err := writeManifest(repoPath, tag, versionDir, archivePath, url); err != nil {
+		return "", err
 	}
 
 	// --- Fix: Use correct versionDir for symlinks ---
@@ -140,6 +199,36 @@ func (m *Manager) InstallVersion(repoPath string, release *github.RepositoryRele
 	if err == nil {
 		executablePath = filepath.Join(m.Cfg.Global.DataDir, relativeExecPath)
 	}
+	return executablePath, nil
+}
+
+// linkPaths lists the shim (Windows) or symlinks (Linux/macOS) that expose
+// installName, the first being the one in the global latest folder.
+func (m *Manager) linkPaths(installName string) []string {
+	latestDir := filepath.Join(m.Cfg.Global.DataDir, "latest")
+	if runtime.GOOS == "windows" {
+		return []string{filepath.Join(latestDir, installName+".cmd")}
+	}
+	paths := []string{filepath.Join(latestDir, installName)}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(homeDir, ".local", "bin", installName))
+	}
+	return paths
+}
+
+// isLinked reports whether the latest-folder link for installName resolves to a file.
+func (m *Manager) isLinked(installName string) bool {
+	fi, err := os.Stat(m.linkPaths(installName)[0])
+	return err == nil && !fi.IsDir()
+}
+
+// linkExecutable points the shim (Windows) or symlinks (Linux/macOS) for
+// installName at executablePath.
+func (m *Manager) linkExecutable(installName, executablePath string) {
+	if m.Cfg.Global.ShimMode {
+		m.writeShims(installName)
+		return
+	}
 
 	if runtime.GOOS == "windows" {
 		globalLatestDir := filepath.Join(m.Cfg.Global.DataDir, "latest")
@@ -173,6 +262,11 @@ func (m *Manager) InstallVersion(repoPath string, release *github.RepositoryRele
 					}
 				}
 				userBinSymlink := filepath.Join(userBin, installName)
+				if isForeignFile(userBinSymlink, false) {
+					// Never delete a binary track did not put there.
+					fmt.Printf("Not linking %s: it is a file track did not create; move it away, then run 'track doctor --fix'\n", userBinSymlink)
+					return
+				}
 				_ = os.Remove(userBinSymlink)
 				err := os.Symlink(executablePath, userBinSymlink)
 				if err == nil {
@@ -183,23 +277,18 @@ func (m *Manager) InstallVersion(repoPath string, release *github.RepositoryRele
 			}
 		}
 	}
-
-	repoCfg.CurrentVersion = version
-	if err := m.Cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config after update: %w", err)
-	}
-
-	fmt.Printf("Successfully installed %s version %s.\n", repoPath, version)
-	return nil
 }
 
-func (m *Manager) AddRepo(repoPath string) error {
+// AddRepo starts tracking repoPath. newRepo carries any settings chosen at
+// add time and may be nil.
+func (m *Manager) AddRepo(repoPath string, newRepo *config.Repo) error {
 	if _, exists := m.Cfg.Repos[repoPath]; exists {
 		return fmt.Errorf("repository '%s' is already being tracked", repoPath)
 	}
-	newRepo := &config.Repo{
-		Path: repoPath,
+	if newRepo == nil {
+		newRepo = &config.Repo{}
 	}
+	newRepo.Path = repoPath
 	m.Cfg.Repos[repoPath] = newRepo
 	if err := m.Cfg.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
@@ -207,3 +296,71 @@ func (m *Manager) AddRepo(repoPath string) error {
 	fmt.Printf("Successfully added '%s' to tracked repositories.\n", repoPath)
 	return nil
 }
+
+// AddAndInstall tracks repoPath and installs release, or the latest release
+// if release is nil. If the install fails, the repo is removed from the
+// config again and the partial install is deleted, so a failed add leaves
+// nothing behind.
+func (m *Manager) AddAndInstall(repoPath string, newRepo *config.Repo, release *github.RepositoryRelease) error {
+	return m.addThen(repoPath, newRepo, func(repoCfg *config.Repo) (string, error) {
+		if release == nil {
+			var err error
+			if release, err = m.LatestRelease(repoPath, repoCfg); err != nil {
+				return "", fmt.Errorf("failed to get latest release for %s: %w", repoPath, err)
+			}
+		}
+		return release.GetTagName(), m.InstallVersion(repoPath, release)
+	})
+}
+
+// AddAndInstallTag is AddAndInstall for the release tagged tag.
+func (m *Manager) AddAndInstallTag(repoPath string, newRepo *config.Repo, tag string) error {
+	return m.addThen(repoPath, newRepo, func(repoCfg *config.Repo) (string, error) {
+		release, err := m.ReleaseByTag(repoPath, repoCfg, tag)
+		if err != nil {
+			return "", err
+		}
+		return tag, m.InstallVersion(repoPath, release)
+	})
+}
+
+// AddAndSync tracks repoPath and installs the asset locked in entry, with
+// the same rollback as AddAndInstall.
+func (m *Manager) AddAndSync(repoPath string, newRepo *config.Repo, entry *lockfile.Entry) error {
+	return m.addThen(repoPath, newRepo, func(*config.Repo) (string, error) {
+		_, err := m.SyncLocked(repoPath, entry)
+		return entry.Tag, err
+	})
+}
+
+// addThen tracks repoPath and runs install, which returns the version it
+// installs. If install fails, the repo is untracked again and the version
+// directory deleted, unless it existed before.
+func (m *Manager) addThen(repoPath string, newRepo *config.Repo, install func(*config.Repo) (string, error)) error {
+	if err := m.AddRepo(repoPath, newRepo); err != nil {
+		return err
+	}
+	existed := make(map[string]bool)
+	for _, version := range m.InstalledVersions(repoPath) {
+		existed[version] = true // installed before; not ours to delete
+	}
+	version, err := install(m.Cfg.Repos[repoPath])
+	if err == nil {
+		return nil
+	}
+
+	if version != "" && !existed[version] {
+		versionDir := m.VersionDir(repoPath, version)
+		os.RemoveAll(versionDir)
+		forgetDigest(repoPath, version)
+		// Drop <name>/general and <name> too if nothing else is installed.
+		os.Remove(filepath.Dir(versionDir))
+		os.Remove(filepath.Dir(filepath.Dir(versionDir)))
+	}
+	delete(m.Cfg.Repos, repoPath)
+	if saveErr := m.Cfg.Save(); saveErr != nil {
+		return fmt.Errorf("%w (and removing '%s' from the config failed: %v)", err, repoPath, saveErr)
+	}
+	fmt.Printf("Removed '%s' from tracked repositories because the first install failed.\n", repoPath)
+	return err
+}
@@ -0,0 +1,140 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/user/track/internal/fsutil"
+	"github.com/user/track/internal/paths"
+)
+
+// configFileRe matches files that belong to the config, not the data dir.
+// They only appear in data_dir when it is also the config directory, as it
+// was by default before the XDG layout.
+var configFileRe = regexp.MustCompile(`^(config\.json(\.\d+|\.lock)?|track\.lock)$`)
+
+// DataMove is one top-level entry of data_dir to move.
+type DataMove struct {
+	From, To string
+}
+
+// PlanDataMigration lists what MigrateData would move to newDir.
+func (m *Manager) PlanDataMigration(newDir string) ([]DataMove, error) {
+	oldDir := filepath.Clean(m.Cfg.Global.DataDir)
+	newDir, err := filepath.Abs(newDir)
+	if err != nil {
+		return nil, err
+	}
+	if newDir == oldDir {
+		return nil, fmt.Errorf("data is already in %s", oldDir)
+	}
+	if within(newDir, oldDir) || within(oldDir, newDir) {
+		return nil, fmt.Errorf("%s and %s must not contain each other", oldDir, newDir)
+	}
+	if entries, err := os.ReadDir(newDir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not empty", newDir)
+	}
+
+	entries, err := os.ReadDir(oldDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data dir: %w", err)
+	}
+	cacheDir, _ := paths.CacheDir()
+	var moves []DataMove
+	for _, e := range entries {
+		if configFileRe.MatchString(e.Name()) {
+			continue
+		}
+		if e.Name() == "run" && oldDir == filepath.Clean(cacheDir) {
+			continue // 'track run' cache shares the old default data dir
+		}
+		moves = append(moves, DataMove{From: filepath.Join(oldDir, e.Name()), To: filepath.Join(newDir, e.Name())})
+	}
+	return moves, nil
+}
+
+// MigrateData moves every installed version to newDir, repoints links and
+// shims at the new location and updates data_dir. If a move fails, entries
+// already moved are put back and the config is left unchanged.
+func (m *Manager) MigrateData(newDir string) error {
+	moves, err := m.PlanDataMigration(newDir)
+	if err != nil {
+		return err
+	}
+	oldDir := filepath.Clean(m.Cfg.Global.DataDir)
+	newDir, _ = filepath.Abs(newDir)
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		return err
+	}
+
+	for i, mv := range moves {
+		if err := fsutil.Move(mv.From, mv.To); err != nil {
+			for _, done := range moves[:i] {
+				if rerr := fsutil.Move(done.To, done.From); rerr != nil {
+					fmt.Printf("Failed to move %s back to %s: %v\n", done.To, done.From, rerr)
+				}
+			}
+			return fmt.Errorf("failed to move %s: %w", mv.From, err)
+		}
+	}
+
+	m.Cfg.Global.DataDir = newDir
+	if err := m.Cfg.Save(); err != nil {
+		return fmt.Errorf("data moved to %s but the config could not be saved; set data_dir manually: %w", newDir, err)
+	}
+
+	relinked := relinkDir(newDir, oldDir, newDir)
+	if homeDir, err := os.UserHomeDir(); err == nil && runtime.GOOS != "windows" {
+		relinked += relinkDir(filepath.Join(homeDir, ".local", "bin"), oldDir, newDir)
+	}
+	fmt.Printf("Moved %d entries from %s to %s and updated %d links.\n", len(moves), oldDir, newDir, relinked)
+	return nil
+}
+
+// relinkDir rewrites symlinks and Windows .cmd shims below dir that point
+// into oldDir so that they point at the same file below newDir.
+func relinkDir(dir, oldDir, newDir string) int {
+	count := 0
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		switch {
+		case d.Type()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil || !filepath.IsAbs(target) || !within(target, oldDir) {
+				return nil
+			}
+			rel, _ := filepath.Rel(oldDir, target)
+			os.Remove(path)
+			if err := os.Symlink(filepath.Join(newDir, rel), path); err != nil {
+				fmt.Printf("Failed to update link %s: %v\n", path, err)
+				return nil
+			}
+			count++
+		case strings.HasSuffix(d.Name(), ".cmd"):
+			data, err := os.ReadFile(path)
+			if err != nil || !strings.Contains(string(data), oldDir) {
+				return nil
+			}
+			updated := strings.ReplaceAll(string(data), oldDir+string(filepath.Separator), newDir+string(filepath.Separator))
+			if err := os.WriteFile(path, []byte(updated), 0755); err != nil {
+				fmt.Printf("Failed to update shim %s: %v\n", path, err)
+				return nil
+			}
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+// within reports whether path is dir or below it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
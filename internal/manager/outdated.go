@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"sync"
+	"time"
+)
+
+// checkConcurrency bounds parallel release lookups to stay friendly with API rate limits.
+const checkConcurrency = 8
+
+// Status is the result of checking one repo for a newer release.
+type Status struct {
+	Repo        string
+	Current     string
+	Latest      string
+	PublishedAt time.Time // zero for sources without release dates
+	Err         error
+}
+
+// Outdated reports whether a newer release than the installed one exists.
+func (s Status) Outdated() bool {
+	return s.Err == nil && s.Latest != s.Current
+}
+
+// CheckUpdates looks up the latest release of each repo in parallel. Nothing
+// is downloaded and the config is not modified. Results keep the input order.
+func (m *Manager) CheckUpdates(repoPaths []string) []Status {
+	results := make([]Status, len(repoPaths))
+	sem := make(chan struct{}, checkConcurrency)
+	var wg sync.WaitGroup
+	for i, repoPath := range repoPaths {
+		wg.Add(1)
+		go func(i int, repoPath string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			repoCfg := m.Cfg.Repos[repoPath]
+			status := Status{Repo: repoPath, Current: repoCfg.CurrentVersion}
+			release, err := m.LatestRelease(repoPath, repoCfg)
+			if err != nil {
+				status.Err = err
+			} else {
+				status.Latest = release.GetTagName()
+				status.PublishedAt = release.GetPublishedAt().Time
+			}
+			results[i] = status
+		}(i, repoPath)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,76 @@
+package manager
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/gh"
+)
+
+// AssetPicker lets the user choose an asset when automatic matching failed
+// with matchErr. scored holds the release's assets best first. It returns
+// the chosen asset, or nil to give up.
+type AssetPicker func(repoCfg *config.Repo, release *github.RepositoryRelease, scored []gh.ScoredAsset, hidden int, matchErr error) *github.ReleaseAsset
+
+// filterCheckReleases is how many earlier releases a derived filter is
+// checked against.
+const filterCheckReleases = 3
+
+// DeriveAssetFilter turns a chosen asset name into an anchored, lower-case
+// asset_filter that should select the same asset in future releases: the
+// version in the name becomes ".*" and everything else is literal.
+func DeriveAssetFilter(assetName, tag string) string {
+	name := regexp.QuoteMeta(strings.ToLower(assetName))
+	version := strings.ToLower(strings.TrimPrefix(tag, "v"))
+	if version != "" {
+		name = strings.ReplaceAll(name, regexp.QuoteMeta(version), ".*")
+	}
+	return "^" + name + "$"
+}
+
+// FilterCheck is the result of replaying a derived filter on one release.
+type FilterCheck struct {
+	Tag      string
+	Expected string // the chosen asset's name with this release's version
+	Got      string // what the filter picked; empty if nothing
+}
+
+// OK reports whether the filter picked the expected asset.
+func (c FilterCheck) OK() bool {
+	return c.Got != "" && c.Got == c.Expected
+}
+
+// CheckAssetFilter replays filter on release and up to filterCheckReleases
+// releases before it, reporting what it would have picked in each. Earlier
+// releases are skipped, not failed, if they cannot be listed.
+func (m *Manager) CheckAssetFilter(repoCfg *config.Repo, filter string, release *github.RepositoryRelease, assetName string) ([]FilterCheck, error) {
+	owner, name, _ := strings.Cut(repoCfg.Path, "/")
+	ctx := context.Background()
+	releases, listErr := gh.NewClient(ctx, "").ListReleases(ctx, owner, name, 2*filterCheckReleases+1)
+
+	trial := *repoCfg
+	trial.AssetFilter = filter
+	version := strings.TrimPrefix(release.GetTagName(), "v")
+	// The release the asset was picked from comes first, then earlier ones.
+	checks := []FilterCheck{}
+	for _, r := range append([]*github.RepositoryRelease{release}, releases...) {
+		if len(checks) == filterCheckReleases+1 {
+			break
+		}
+		if len(checks) > 0 && (r.GetTagName() == release.GetTagName() || r.GetDraft() || (r.GetPrerelease() && !repoCfg.IncludePrerelease)) {
+			continue
+		}
+		check := FilterCheck{
+			Tag:      r.GetTagName(),
+			Expected: strings.ReplaceAll(assetName, version, strings.TrimPrefix(r.GetTagName(), "v")),
+		}
+		if asset, err := gh.FindCompatibleAsset(r, &trial, &m.Cfg.Global); err == nil {
+			check.Got = asset.GetName()
+		}
+		checks = append(checks, check)
+	}
+	return checks, listErr
+}
@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/archiver"
+	"github.com/user/track/internal/config"
+)
+
+// Plan describes what installing a release would do. Building a plan runs
+// the full resolution but never touches the filesystem or the config.
+type Plan struct {
+	Repo       string
+	Current    string
+	Version    string
+	UpToDate   bool
+	Asset      string // empty when building from source
+	URL        string
+	Size       int64 // download size in bytes, 0 if unknown
+	Build      bool  // built from source instead of downloading an asset
+	VersionDir string
+	Executable string   // resolved if the version is already on disk, otherwise a best guess
+	Links      []string // links that would be (re)created
+}
+
+// PlanUpdate resolves what 'track update' would do for a tracked repo.
+func (m *Manager) PlanUpdate(repoPath string, force bool) (*Plan, error) {
+	repoCfg, ok := m.Cfg.Repos[repoPath]
+	if !ok {
+		return nil, fmt.Errorf("repository '%s' not tracked", repoPath)
+	}
+	release, err := m.LatestRelease(repoPath, repoCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest release for %s: %w", repoPath, err)
+	}
+	if !force && release.GetTagName() == repoCfg.CurrentVersion && m.isLinked(m.InstallName(repoPath, repoCfg)) {
+		return &Plan{Repo: repoPath, Current: repoCfg.CurrentVersion, Version: release.GetTagName(), UpToDate: true}, nil
+	}
+	return m.PlanInstall(repoPath, repoCfg, release)
+}
+
+// PlanInstall resolves what installing release for repoPath would do.
+// repoCfg does not need to be tracked yet.
+func (m *Manager) PlanInstall(repoPath string, repoCfg *config.Repo, release *github.RepositoryRelease) (*Plan, error) {
+	version := release.GetTagName()
+	_, name, _ := strings.Cut(repoPath, "/")
+	installName := m.InstallName(repoPath, repoCfg)
+	plan := &Plan{
+		Repo:       repoPath,
+		Current:    repoCfg.CurrentVersion,
+		Version:    version,
+		VersionDir: m.VersionDir(repoPath, version),
+		Links:      m.linkPaths(installName),
+	}
+
+	if repoCfg.Build != nil && repoCfg.Build.Always {
+		plan.Build = true
+	} else if asset, err := m.SelectAsset(repoCfg, release); err == nil {
+		plan.Asset = asset.GetName()
+		plan.URL = asset.GetBrowserDownloadURL()
+		plan.Size = int64(asset.GetSize())
+	} else if repoCfg.Build != nil {
+		plan.Build = true
+	} else {
+		return nil, fmt.Errorf("could not find compatible asset for %s in version %s: %w", repoPath, version, err)
+	}
+	if plan.Build {
+		url, err := sourceTarballURL(repoPath, repoCfg, release)
+		if err != nil {
+			return nil, err
+		}
+		plan.URL = url
+	}
+
+	if exe, err := archiver.FindExecutable(plan.VersionDir, name, installName); err == nil {
+		plan.Executable = exe
+	} else {
+		plan.Executable = filepath.Join(plan.VersionDir, installName)
+	}
+	return plan, nil
+}
@@ -0,0 +1,147 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/user/track/internal/archiver"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/project"
+)
+
+// EnsureVersion makes sure a specific version of a tracked repo is on disk,
+// downloading it if needed, and returns its executable. Links and the
+// current version are left untouched.
+func (m *Manager) EnsureVersion(repoPath, version string) (string, error) {
+	repoCfg, ok := m.Cfg.Repos[repoPath]
+	if !ok {
+		return "", fmt.Errorf("repository '%s' not tracked", repoPath)
+	}
+	if exe, err := m.installedExecutable(repoPath, repoCfg, version); err == nil {
+		return exe, nil
+	}
+	release, err := m.ReleaseByTag(repoPath, repoCfg, version)
+	if err != nil {
+		return "", err
+	}
+	stage, err := m.stageDir(repoPath, version)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(stage)
+	exe, err := m.fetch(repoPath, repoCfg, release, stage)
+	if err != nil {
+		return "", err
+	}
+	if err := m.smokeTest(repoCfg, version, exe); err != nil {
+		return "", m.rejectVersion(repoPath, repoCfg, version, err)
+	}
+	if exe, err = m.commitStage(repoPath, version, stage, exe); err != nil {
+		return "", err
+	}
+	if m.Cfg.Global.LinkVersions {
+		m.linkVersioned(m.InstallName(repoPath, repoCfg), version, exe)
+	}
+	return exe, nil
+}
+
+// Relink recreates the links (or shims) of a repo's current version.
+func (m *Manager) Relink(repoPath string) error {
+	repoCfg, ok := m.Cfg.Repos[repoPath]
+	if !ok {
+		return fmt.Errorf("repository '%s' not tracked", repoPath)
+	}
+	if repoCfg.CurrentVersion == "" {
+		return fmt.Errorf("%s is not installed", repoPath)
+	}
+	exe, err := m.installedExecutable(repoPath, repoCfg, repoCfg.CurrentVersion)
+	if err != nil {
+		return err
+	}
+	m.linkExecutable(m.InstallName(repoPath, repoCfg), exe)
+	return nil
+}
+
+// ResolveShim finds the executable a shim named installName should run from
+// dir: the version pinned by the nearest project manifest, falling back to
+// the global current version.
+func (m *Manager) ResolveShim(installName, dir string) (string, error) {
+	var repoPath string
+	for key, repoCfg := range m.Cfg.Repos {
+		if m.InstallName(key, repoCfg) == installName {
+			repoPath = key
+			break
+		}
+	}
+	if repoPath == "" {
+		return "", fmt.Errorf("no tracked repository installs '%s'", installName)
+	}
+	repoCfg := m.Cfg.Repos[repoPath]
+
+	manifest, err := project.Find(dir)
+	if err != nil {
+		return "", err
+	}
+	if manifest != nil {
+		if version, ok := manifest.Tools[repoPath]; ok {
+			exe, err := m.installedExecutable(repoPath, repoCfg, version)
+			if err != nil {
+				return "", fmt.Errorf("%s %s (required by %s) is not installed; run 'track install'", repoPath, version, manifest.Path)
+			}
+			return exe, nil
+		}
+	}
+
+	if repoCfg.CurrentVersion == "" {
+		return "", fmt.Errorf("%s is not installed", repoPath)
+	}
+	return m.installedExecutable(repoPath, repoCfg, repoCfg.CurrentVersion)
+}
+
+// installedExecutable finds the executable of a version already on disk.
+func (m *Manager) installedExecutable(repoPath string, repoCfg *config.Repo, version string) (string, error) {
+	versionDir := m.VersionDir(repoPath, version)
+	if _, err := os.Stat(versionDir); err != nil {
+		return "", fmt.Errorf("%s %s is not installed", repoPath, version)
+	}
+	return m.findExecutable(repoPath, repoCfg, versionDir)
+}
+
+// findExecutable locates the repo's executable under dir.
+func (m *Manager) findExecutable(repoPath string, repoCfg *config.Repo, dir string) (string, error) {
+	_, name, _ := strings.Cut(repoPath, "/")
+	return archiver.FindExecutable(dir, name, m.InstallName(repoPath, repoCfg))
+}
+
+// writeShims writes scripts at every link path that hand off to 'track shim',
+// which picks the version per directory.
+func (m *Manager) writeShims(installName string) {
+	trackPath, err := os.Executable()
+	if err != nil {
+		fmt.Printf("Failed to locate track executable for shims: %v\n", err)
+		return
+	}
+	if resolved, err := filepath.EvalSymlinks(trackPath); err == nil {
+		trackPath = resolved
+	}
+
+	content := "#!/bin/sh\nexec \"" + trackPath + "\" shim \"" + installName + "\" \"$@\"\n"
+	if runtime.GOOS == "windows" {
+		content = "@echo off\r\n\"" + trackPath + "\" shim \"" + installName + "\" %*\r\n"
+	}
+	for _, path := range m.linkPaths(installName) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Printf("Failed to create shim %s: %v\n", path, err)
+			continue
+		}
+		os.Remove(path)
+		if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+			fmt.Printf("Failed to create shim %s: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("Created shim: %s\n", path)
+	}
+}
@@ -0,0 +1,122 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/paths"
+)
+
+// RunDir is the reusable directory 'track run' unpacks a release into.
+func RunDir(repoPath, version string) (string, error) {
+	dir, err := runCacheDir()
+	if err != nil {
+		return "", err
+	}
+	owner, name, _ := strings.Cut(repoPath, "/")
+	return filepath.Join(dir, owner, name, version), nil
+}
+
+// runCacheDir holds 'track run' downloads. Whatever is found there is run
+// again later, so there is no fallback to a shared location such as the
+// temp dir, where another user could plant files.
+func runCacheDir() (string, error) {
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return "", fmt.Errorf("no cache directory for 'track run' downloads: %w", err)
+	}
+	dir = filepath.Join(dir, "run")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// FetchEphemeral resolves and downloads a release of a repo that does not
+// need to be tracked into its run directory, reusing an earlier download if
+// present. An empty tag means the latest release. config.Repos is not touched.
+func (m *Manager) FetchEphemeral(repoPath, tag string) (string, *github.RepositoryRelease, error) {
+	repoCfg := &config.Repo{Path: repoPath}
+	if tracked, ok := m.Cfg.Repos[repoPath]; ok {
+		repoCfg = tracked
+	}
+
+	var release *github.RepositoryRelease
+	var err error
+	if tag == "" {
+		release, err = m.LatestRelease(repoPath, repoCfg)
+	} else {
+		release, err = m.ReleaseByTag(repoPath, repoCfg, tag)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	runDir, err := RunDir(repoPath, release.GetTagName())
+	if err != nil {
+		return "", nil, err
+	}
+	if exe, err := m.findExecutable(repoPath, repoCfg, runDir); err == nil {
+		return exe, release, nil
+	}
+	exe, err := m.fetch(repoPath, repoCfg, release, runDir)
+	if err != nil {
+		os.RemoveAll(runDir)
+		return "", nil, err
+	}
+	return exe, release, nil
+}
+
+// PromoteRun starts tracking a repo fetched by FetchEphemeral and makes that
+// release its current version, moving the run directory into place when possible.
+func (m *Manager) PromoteRun(repoPath string, release *github.RepositoryRelease) error {
+	if _, ok := m.Cfg.Repos[repoPath]; !ok {
+		if err := m.AddRepo(repoPath, nil); err != nil {
+			return err
+		}
+	}
+	version := release.GetTagName()
+	versionDir := m.VersionDir(repoPath, version)
+	if _, err := os.Stat(versionDir); os.IsNotExist(err) {
+		os.MkdirAll(filepath.Dir(versionDir), 0755)
+		runDir, err := RunDir(repoPath, version)
+		if err == nil {
+			err = os.Rename(runDir, versionDir)
+		}
+		if err != nil {
+			// Different filesystems: download again into the data dir.
+			return m.InstallVersion(repoPath, release)
+		}
+		if err := recordDigest(repoPath, version, versionDir); err != nil {
+			return fmt.Errorf("failed to record the manifest digest of %s %s: %w", repoPath, version, err)
+		}
+	}
+	return m.Activate(repoPath, version)
+}
+
+// Activate makes an installed version current: it links its executable and
+// records it in the config.
+func (m *Manager) Activate(repoPath, version string) error {
+	repoCfg, ok := m.Cfg.Repos[repoPath]
+	if !ok {
+		return fmt.Errorf("repository '%s' not tracked", repoPath)
+	}
+	exe, err := m.installedExecutable(repoPath, repoCfg, version)
+	if err != nil {
+		return err
+	}
+	m.linkExecutable(m.InstallName(repoPath, repoCfg), exe)
+	if m.Cfg.Global.LinkVersions {
+		m.linkVersioned(m.InstallName(repoPath, repoCfg), version, exe)
+	}
+	repoCfg.CurrentVersion = version
+	if err := m.Cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config after update: %w", err)
+	}
+	fmt.Printf("Successfully installed %s version %s.\n", repoPath, version)
+	return nil
+}
@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/track/internal/adopt"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/semver"
+)
+
+// defaultSmokeTimeout bounds a smoke test unless smoke_timeout is set.
+const defaultSmokeTimeout = 10 * time.Second
+
+// smokeCommand is the repo's smoke_test, else the global default_smoke_test,
+// else DefaultSmokeTest.
+func (m *Manager) smokeCommand(repoCfg *config.Repo) string {
+	switch {
+	case repoCfg.SmokeTest != "":
+		return repoCfg.SmokeTest
+	case m.Cfg.Global.DefaultSmokeTest != "":
+		return m.Cfg.Global.DefaultSmokeTest
+	}
+	return config.DefaultSmokeTest
+}
+
+// smokeTest runs the repo's smoke_test against a freshly fetched executable
+// before it is linked, catching wrong architectures, missing shared
+// libraries and the like. A configured smoke_test must succeed; the default
+// only fails if the executable cannot run, since some tools reject
+// --version. If the command asks for the version, the version
+// printed is compared with tag; a mismatch only warns, since tools format
+// their versions in many ways.
+func (m *Manager) smokeTest(repoCfg *config.Repo, tag, exe string) error {
+	tmpl := m.smokeCommand(repoCfg)
+	if tmpl == config.SmokeTestNone {
+		return nil
+	}
+	args, err := config.RenderSmokeTest(tmpl, exe, tag)
+	if err != nil {
+		return fmt.Errorf("invalid smoke_test %q: %w", tmpl, err)
+	}
+	timeout := defaultSmokeTimeout
+	if m.Cfg.Global.SmokeTimeout > 0 {
+		timeout = time.Duration(m.Cfg.Global.SmokeTimeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// Run it in an empty directory: files the tool writes to its working
+	// directory must not end up in the version directory, whose manifest
+	// is already written.
+	workDir, err := os.MkdirTemp("", "track-smoke-")
+	if err != nil {
+		return fmt.Errorf("could not create a directory for the smoke test: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = workDir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err = cmd.Run()
+	display := strings.ReplaceAll(strings.Join(args, " "), exe, filepath.Base(exe))
+	if ctx.Err() != nil {
+		return fmt.Errorf("smoke test '%s' timed out after %s", display, timeout)
+	}
+	configured := repoCfg.SmokeTest != "" || m.Cfg.Global.DefaultSmokeTest != ""
+	if err != nil && !configured && ranAndExited(err, out.String()) {
+		// Not every tool knows --version (helm and kubectl want 'version'),
+		// and one that starts and exits on its own has proven what the
+		// default test is for: the right architecture and its libraries.
+		fmt.Printf("Smoke test passed: %s started and exited with status %d.\n", filepath.Base(exe), err.(*exec.ExitError).ExitCode())
+		return nil
+	}
+	if err != nil {
+		if line := lastLine(out.String()); line != "" {
+			return fmt.Errorf("smoke test '%s' failed: %v: %s", display, err, line)
+		}
+		return fmt.Errorf("smoke test '%s' failed: %v", display, err)
+	}
+	fmt.Printf("Smoke test passed: %s\n", display)
+
+	if strings.Contains(tmpl, "version") {
+		if reported := adopt.ParseVersion(out.String()); reported != "" && !semver.Equal(reported, tag) {
+			if _, ok := semver.Parse(tag); ok {
+				fmt.Printf("Warning: %s reports version %s, but the release is %s.\n", filepath.Base(exe), reported, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// ranAndExited reports whether err from running a command is a plain
+// non-zero exit status rather than a failure to run it at all: a missing
+// file or loader, a wrong architecture (ENOEXEC), missing shared libraries
+// (status 127 from the loader), no permission (126) or a signal.
+func ranAndExited(err error, output string) bool {
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok {
+		return false
+	}
+	code := exitErr.ExitCode()
+	if code <= 0 || code == 126 || code == 127 {
+		return false
+	}
+	return !strings.Contains(output, "error while loading shared libraries")
+}
+
+// lastLine is the last non-empty line of output, where errors such as
+// "error while loading shared libraries" usually end up.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if len(line) > 200 {
+		line = line[:200] + "..."
+	}
+	return line
+}
+
+// rejectVersion records a version that failed its smoke test. It was
+// fetched into a staging directory, which the caller discards, so installed
+// versions and the current links are untouched.
+func (m *Manager) rejectVersion(repoPath string, repoCfg *config.Repo, version string, cause error) error {
+	repoCfg.LastFailure = &config.Failure{
+		Version: version,
+		Time:    time.Now().UTC().Format(time.RFC3339),
+		Error:   cause.Error(),
+	}
+	if err := m.Cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config after failed install: %w", err)
+	}
+	if repoCfg.CurrentVersion == "" {
+		return fmt.Errorf("%s %s was not installed: %w", repoPath, version, cause)
+	}
+	return fmt.Errorf("%s %s was not linked, keeping %s: %w", repoPath, version, repoCfg.CurrentVersion, cause)
+}
@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/gh"
+	"github.com/user/track/internal/source"
+)
+
+// maxTags bounds how many tags are fetched when looking for the newest one.
+const maxTags = 500
+
+// LatestRelease resolves the newest release of a repo from its configured source.
+func (m *Manager) LatestRelease(repoPath string, repoCfg *config.Repo) (*github.RepositoryRelease, error) {
+	ctx := context.Background()
+	switch repoCfg.Source {
+	case "", config.SourceGitHub:
+		owner, name, _ := strings.Cut(repoPath, "/")
+		client := gh.NewClient(ctx, "")
+		return client.GetLatestRelease(ctx, owner, name, repoCfg.IncludePrerelease)
+	case config.SourceURL:
+		return source.NewURL(repoCfg).LatestRelease(ctx)
+	case config.SourceTags:
+		owner, name, _ := strings.Cut(repoPath, "/")
+		client := gh.NewClient(ctx, "")
+		tags, err := client.ListTags(ctx, owner, name, maxTags)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := source.LatestTag(tags, repoCfg.IncludePrerelease)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s", err, repoPath)
+		}
+		return source.NewURL(repoCfg).Release(tag)
+	}
+	return nil, fmt.Errorf("unknown source '%s' for %s", repoCfg.Source, repoPath)
+}
+
+// ReleaseByTag resolves a specific release of a repo from its configured source.
+func (m *Manager) ReleaseByTag(repoPath string, repoCfg *config.Repo, tag string) (*github.RepositoryRelease, error) {
+	ctx := context.Background()
+	switch repoCfg.Source {
+	case "", config.SourceGitHub:
+		owner, name, _ := strings.Cut(repoPath, "/")
+		client := gh.NewClient(ctx, "")
+		return client.GetReleaseByTag(ctx, owner, name, tag)
+	case config.SourceURL, config.SourceTags:
+		return source.NewURL(repoCfg).Release(tag)
+	}
+	return nil, fmt.Errorf("unknown source '%s' for %s", repoCfg.Source, repoPath)
+}
+
+// SelectAsset picks the asset to install from release. Releases built from a
+// URL template carry exactly one asset that is already platform specific.
+// If matching fails or is ambiguous and m.PickAsset is set, the user is
+// asked to choose; declining keeps the matcher's own choice, if any.
+func (m *Manager) SelectAsset(repoCfg *config.Repo, release *github.RepositoryRelease) (*github.ReleaseAsset, error) {
+	if usesTemplate(repoCfg) {
+		if len(release.Assets) == 0 {
+			return nil, fmt.Errorf("release %s has no download URL", release.GetTagName())
+		}
+		return release.Assets[0], nil
+	}
+	asset, err := gh.FindCompatibleAsset(release, repoCfg, &m.Cfg.Global)
+	pickErr := err
+	if tied := gh.AmbiguousAssets(release, repoCfg, &m.Cfg.Global); err == nil && len(tied) > 1 {
+		names := make([]string, len(tied))
+		for i, a := range tied {
+			names[i] = a.GetName()
+		}
+		pickErr = fmt.Errorf("%d assets fit equally well (%s)", len(tied), strings.Join(names, ", "))
+	}
+	if pickErr != nil && m.PickAsset != nil {
+		// Remember the choice so that a plan followed by the install asks once.
+		key := repoCfg.Path + "@" + release.GetTagName()
+		if picked, ok := m.picked[key]; ok {
+			return picked, nil
+		}
+		scored, hidden := gh.ScoreAssets(release, repoCfg, &m.Cfg.Global)
+		if len(scored) > 0 {
+			if picked := m.PickAsset(repoCfg, release, scored, hidden, pickErr); picked != nil {
+				if m.picked == nil {
+					m.picked = make(map[string]*github.ReleaseAsset)
+				}
+				m.picked[key] = picked
+				return picked, nil
+			}
+		}
+	}
+	return asset, err
+}
+
+// usesTemplate reports whether downloads for repoCfg come from its url_template.
+func usesTemplate(repoCfg *config.Repo) bool {
+	return repoCfg.Source == config.SourceURL || repoCfg.Source == config.SourceTags
+}
@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stageDir creates an empty directory to fetch a version into. Installs are
+// downloaded, unpacked and smoke tested there and only then moved into the
+// version directory, so a failed download, build or smoke test never
+// touches an installed version. It lives in data_dir so that the move is a
+// rename on the same filesystem.
+func (m *Manager) stageDir(repoPath, version string) (string, error) {
+	dir := filepath.Join(m.Cfg.Global.DataDir, ".staging")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("could not create staging directory: %w", err)
+	}
+	_, name, _ := strings.Cut(repoPath, "/")
+	// MkdirTemp refuses patterns with separators, which GitHub tags may have.
+	pattern := strings.Map(func(r rune) rune {
+		if os.IsPathSeparator(uint8(r)) {
+			return '_'
+		}
+		return r
+	}, name+"-"+version+"-")
+	stage, err := os.MkdirTemp(dir, pattern)
+	if err != nil {
+		return "", fmt.Errorf("could not create staging directory: %w", err)
+	}
+	// MkdirTemp creates it owner-only; it becomes the version directory.
+	if err := os.Chmod(stage, 0755); err != nil {
+		os.RemoveAll(stage)
+		return "", fmt.Errorf("could not create staging directory: %w", err)
+	}
+	return stage, nil
+}
+
+// commitStage moves a staged version of repoPath into its version
+// directory, replacing an earlier install of the same version, records the
+// digest of its manifest and returns the path exe has there.
+func (m *Manager) commitStage(repoPath, version, stage, exe string) (string, error) {
+	versionDir := m.VersionDir(repoPath, version)
+	rel, err := filepath.Rel(stage, exe)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(versionDir), 0755); err != nil {
+		return "", fmt.Errorf("could not create version directory: %w", err)
+	}
+	old := stage + ".old"
+	if err := os.Rename(versionDir, old); err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("could not replace %s: %w", versionDir, err)
+	}
+	if err := os.Rename(stage, versionDir); err != nil {
+		os.Rename(old, versionDir)
+		return "", fmt.Errorf("could not move the new install into %s: %w", versionDir, err)
+	}
+	os.RemoveAll(old)
+	if err := recordDigest(repoPath, version, versionDir); err != nil {
+		return "", fmt.Errorf("failed to record the manifest digest of %s %s: %w", repoPath, version, err)
+	}
+	return filepath.Join(versionDir, rel), nil
+}
@@ -0,0 +1,182 @@
+package manager
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/track/internal/lockfile"
+	"github.com/user/track/internal/project"
+	"github.com/user/track/internal/semver"
+)
+
+// VersionDirInfo is an installed version directory on disk.
+type VersionDirInfo struct {
+	Repo     string // owner/repo, or the directory name for orphans
+	Version  string // empty for an orphaned repository directory
+	Path     string
+	Bytes    int64
+	Modified time.Time
+}
+
+// TidyOptions selects which old versions 'track tidy' deletes.
+type TidyOptions struct {
+	Keep      int           // previous versions to keep besides the current one
+	OlderThan time.Duration // only delete versions installed longer ago than this
+	Pinned    []Pin         // versions never deleted, see PinnedVersions
+}
+
+// Pin is a version that a lockfile or project manifest relies on.
+type Pin struct {
+	Repo    string
+	Version string
+	Source  string // the file that pins it
+}
+
+// PinnedVersions lists the versions pinned by track.lock and by the project
+// manifest nearest to dir. Manifests of other projects cannot be found and
+// are not included.
+func PinnedVersions(dir string) []Pin {
+	var pins []Pin
+	if path, err := lockfile.DefaultPath(); err == nil {
+		if lock, err := lockfile.Load(path); err == nil {
+			for repo, entry := range lock.Repos {
+				pins = append(pins, Pin{repo, entry.Tag, path})
+			}
+		}
+	}
+	if mf, err := project.Find(dir); err == nil && mf != nil {
+		for repo, tag := range mf.Tools {
+			pins = append(pins, Pin{repo, tag, mf.Path})
+		}
+	}
+	return pins
+}
+
+// pinnedBy returns the file pinning version of repo, or "".
+func (o TidyOptions) pinnedBy(repo, version string) string {
+	for _, p := range o.Pinned {
+		if strings.EqualFold(p.Repo, repo) && (p.Version == version || semver.Equal(p.Version, version)) {
+			return p.Source
+		}
+	}
+	return ""
+}
+
+// VersionDirs lists every installed version of a repo, newest first.
+func (m *Manager) VersionDirs(repoPath string) []VersionDirInfo {
+	var dirs []VersionDirInfo
+	for _, version := range m.InstalledVersions(repoPath) {
+		dirs = append(dirs, dirInfo(repoPath, version, m.VersionDir(repoPath, version)))
+	}
+	sort.Slice(dirs, func(i, j int) bool { return semver.Compare(dirs[i].Version, dirs[j].Version) > 0 })
+	return dirs
+}
+
+// TidyCandidates lists the version directories 'track tidy' would delete:
+// every version of the given tracked repos except the current one, pinned
+// ones and, per opts, the newest previous ones and recent ones. Repos
+// without a current version are skipped, since nothing says which of
+// their versions is in use.
+func (m *Manager) TidyCandidates(repos []string, opts TidyOptions) []VersionDirInfo {
+	var candidates []VersionDirInfo
+	for _, repoKey := range repos {
+		repo := m.Cfg.Repos[repoKey]
+		if repo.CurrentVersion == "" {
+			continue
+		}
+		kept := 0
+		for _, dir := range m.VersionDirs(repoKey) {
+			if dir.Version == repo.CurrentVersion {
+				continue
+			}
+			if source := opts.pinnedBy(repoKey, dir.Version); source != "" {
+				fmt.Printf("Keeping %s %s: pinned by %s\n", repoKey, dir.Version, source)
+				continue
+			}
+			if kept < opts.Keep {
+				kept++
+				continue
+			}
+			if opts.OlderThan > 0 && time.Since(dir.Modified) < opts.OlderThan {
+				continue
+			}
+			candidates = append(candidates, dir)
+		}
+	}
+	return candidates
+}
+
+// Orphans lists repository directories in data_dir that belong to no
+// tracked repository, e.g. left behind by 'track remove', which keeps the
+// installed files.
+func (m *Manager) Orphans() []VersionDirInfo {
+	tracked := make(map[string]bool)
+	for repoPath := range m.Cfg.Repos {
+		_, name, _ := strings.Cut(repoPath, "/")
+		tracked[name] = true
+	}
+	entries, err := os.ReadDir(m.Cfg.Global.DataDir)
+	if err != nil {
+		return nil
+	}
+	var orphans []VersionDirInfo
+	for _, entry := range entries {
+		if !entry.IsDir() || tracked[entry.Name()] {
+			continue
+		}
+		path := filepath.Join(m.Cfg.Global.DataDir, entry.Name())
+		// Only directories laid out by track; latest, adopted and anything
+		// the user put there are left alone.
+		if fi, err := os.Stat(filepath.Join(path, "general")); err != nil || !fi.IsDir() {
+			continue
+		}
+		orphans = append(orphans, dirInfo(entry.Name(), "", path))
+	}
+	return orphans
+}
+
+func dirInfo(repo, version, path string) VersionDirInfo {
+	info := VersionDirInfo{Repo: repo, Version: version, Path: path, Bytes: DirSize(path)}
+	if fi, err := os.Stat(path); err == nil {
+		info.Modified = fi.ModTime()
+	}
+	return info
+}
+
+// ParseAge parses a duration that also accepts days and weeks, e.g. 30d,
+// 2w or 36h.
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			f, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(f * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(s)
+}
+
+// DirSize returns the total size of regular files under path.
+func DirSize(path string) int64 {
+	var total int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				total += info.Size()
+			}
+		}
+		return nil
+	})
+	return total
+}
@@ -0,0 +1,136 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/downloader"
+	"github.com/user/track/internal/fsutil"
+	"github.com/user/track/internal/manifest"
+)
+
+// writeManifest records the files of a freshly installed versionDir and the
+// digest of the download they came from.
+func writeManifest(repoPath, tag, versionDir, download, url string) error {
+	digest, err := downloader.SHA256(download)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", filepath.Base(download), err)
+	}
+	asset := &manifest.Asset{Name: filepath.Base(download), URL: url, SHA256: digest}
+	if _, err := manifest.Create(versionDir, repoPath, tag, asset); err != nil {
+		return fmt.Errorf("failed to write manifest for %s %s: %w", repoPath, tag, err)
+	}
+	return nil
+}
+
+// ErrNoDigest is returned by Verify for versions whose manifest digest was
+// never recorded, e.g. installed by an older track. The files were checked,
+// but against a manifest that could have been rewritten along with them.
+var ErrNoDigest = errors.New("the manifest's digest was not recorded at install time, so the manifest itself cannot be trusted; reinstall to record it")
+
+// digestPath is where the digest of a version's manifest is kept: in the
+// config directory, out of reach of anything that can write to data_dir
+// but not to the user's config.
+func digestPath(repoPath, version string) (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	owner, name, _ := strings.Cut(repoPath, "/")
+	return filepath.Join(dir, "manifests", owner, name, version+".sha256"), nil
+}
+
+// recordDigest stores the digest of versionDir's manifest, once the version
+// is in place.
+func recordDigest(repoPath, version, versionDir string) error {
+	digest, err := manifest.Digest(versionDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	path, err := digestPath(repoPath, version)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, []byte(digest+"\n"), 0600)
+}
+
+// forgetDigest deletes the recorded digest of a removed version.
+func forgetDigest(repoPath, version string) {
+	if path, err := digestPath(repoPath, version); err == nil {
+		os.Remove(path)
+	}
+}
+
+// Verify compares an installed version with the manifest written when it
+// was installed, after checking the manifest against the digest recorded
+// outside data_dir. It returns an error satisfying os.IsNotExist if the
+// version is not installed or has no manifest, and ErrNoDigest along with
+// the results if no digest was recorded.
+func (m *Manager) Verify(repoPath, version string) (*manifest.Manifest, []manifest.Problem, error) {
+	versionDir := m.VersionDir(repoPath, version)
+	if _, err := os.Stat(versionDir); err != nil {
+		return nil, nil, err
+	}
+	mf, err := manifest.Read(versionDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	problems, err := mf.Verify(versionDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mf.Repo != repoPath || mf.Tag != version {
+		problems = append(problems, manifest.Problem{
+			Path:    manifest.FileName,
+			Message: fmt.Sprintf("written for %s %s, not %s %s", mf.Repo, mf.Tag, repoPath, version),
+		})
+	}
+
+	path, err := digestPath(repoPath, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	recorded, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return mf, problems, ErrNoDigest
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	digest, err := manifest.Digest(versionDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if want := strings.TrimSpace(string(recorded)); digest != want {
+		problems = append(problems, manifest.Problem{
+			Path:    manifest.FileName,
+			Message: fmt.Sprintf("manifest changed: sha256 %s, recorded in %s as %s", digest, path, want),
+		})
+	}
+	return mf, problems, nil
+}
+
+// InstalledVersions lists the version directories of a repo on disk.
+func (m *Manager) InstalledVersions(repoPath string) []string {
+	entries, err := os.ReadDir(filepath.Dir(m.VersionDir(repoPath, "x")))
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions
+}
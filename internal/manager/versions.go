@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// linkVersioned exposes one version as name@version next to the regular
+// links, so several versions can be invoked side by side.
+func (m *Manager) linkVersioned(installName, version, executablePath string) {
+	for _, path := range m.linkPaths(versionedName(installName, version)) {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Printf("Failed to create versioned link %s: %v\n", path, err)
+			continue
+		}
+		os.Remove(path)
+		var err error
+		if runtime.GOOS == "windows" {
+			err = os.WriteFile(path, []byte("@echo off\r\n\""+executablePath+"\" %*\r\n"), 0755)
+		} else {
+			err = os.Symlink(executablePath, path)
+		}
+		if err != nil {
+			fmt.Printf("Failed to create versioned link %s: %v\n", path, err)
+			continue
+		}
+		fmt.Printf("Created versioned link: %s -> %s\n", path, executablePath)
+	}
+}
+
+// RemoveVersion deletes an installed version directory and its name@version
+// links, or an orphaned repository directory and any links into it.
+func (m *Manager) RemoveVersion(dir VersionDirInfo) error {
+	if err := os.RemoveAll(dir.Path); err != nil {
+		return err
+	}
+	if dir.Version == "" {
+		for _, linkDir := range []string{filepath.Join(m.Cfg.Global.DataDir, "latest"), m.linkDir()} {
+			entries, _ := os.ReadDir(linkDir)
+			for _, entry := range entries {
+				path := filepath.Join(linkDir, entry.Name())
+				if target, err := os.Readlink(path); err == nil && within(target, dir.Path) {
+					os.Remove(path)
+				}
+			}
+		}
+		return nil
+	}
+	forgetDigest(dir.Repo, dir.Version)
+	if repoCfg, ok := m.Cfg.Repos[dir.Repo]; ok {
+		for _, path := range m.linkPaths(versionedName(m.InstallName(dir.Repo, repoCfg), dir.Version)) {
+			os.Remove(path)
+		}
+	}
+	return nil
+}
+
+func versionedName(installName, version string) string {
+	return installName + "@" + version
+}
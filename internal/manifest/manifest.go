@@ -0,0 +1,218 @@
+// Package manifest records the files of an installed version, with their
+// sizes, modes and digests, so that later changes to them can be detected.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/user/track/internal/fsutil"
+)
+
+// FileName is the manifest's name inside a version directory.
+const FileName = ".track-manifest.json"
+
+// formatVersion is bumped when the manifest layout changes incompatibly.
+const formatVersion = 1
+
+// Manifest lists what was installed into a version directory.
+type Manifest struct {
+	Version int       `json:"version"`
+	Repo    string    `json:"repo"`
+	Tag     string    `json:"tag"`
+	Created time.Time `json:"created"`
+	Asset   *Asset    `json:"asset,omitempty"`
+	Files   []File    `json:"files"`
+}
+
+// Asset is the download the files came from: the release asset, or the
+// source tarball for builds.
+type Asset struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// File is one file or symlink in the version directory.
+type File struct {
+	Path   string `json:"path"` // slash-separated, relative to the version directory
+	Size   int64  `json:"size"`
+	Mode   string `json:"mode"` // permission bits in octal, e.g. "0755"
+	SHA256 string `json:"sha256,omitempty"`
+	Link   string `json:"link,omitempty"` // target, for symlinks
+}
+
+// Problem is a difference between a manifest and the directory on disk.
+type Problem struct {
+	Path    string
+	Message string
+}
+
+// Scan lists every file under dir except the manifest itself.
+func Scan(dir string) ([]File, error) {
+	var files []File
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == FileName {
+			return nil
+		}
+		f, err := scanFile(path)
+		if err != nil {
+			return err
+		}
+		f.Path = filepath.ToSlash(rel)
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func scanFile(path string) (File, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return File{}, err
+	}
+	f := File{Size: fi.Size(), Mode: fmt.Sprintf("%04o", fi.Mode().Perm())}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		f.Link, err = os.Readlink(path)
+		return f, err
+	}
+	f.SHA256, err = hashFile(path)
+	return f, err
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Create scans dir and writes its manifest.
+func Create(dir, repo, tag string, asset *Asset) (*Manifest, error) {
+	files, err := Scan(dir)
+	if err != nil {
+		return nil, err
+	}
+	m := &Manifest{
+		Version: formatVersion,
+		Repo:    repo,
+		Tag:     tag,
+		Created: time.Now().UTC().Truncate(time.Second),
+		Asset:   asset,
+		Files:   files,
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return m, fsutil.WriteFileAtomic(filepath.Join(dir, FileName), append(data, '\n'), 0644)
+}
+
+// Read loads the manifest of dir. The error satisfies os.IsNotExist if the
+// version was installed without one.
+func Read(dir string) (*Manifest, error) {
+	path := filepath.Join(dir, FileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if m.Version > formatVersion {
+		return nil, fmt.Errorf("%s has version %d, this track supports up to %d", path, m.Version, formatVersion)
+	}
+	return &m, nil
+}
+
+func reported(problems []Problem, path string) bool {
+	for _, p := range problems {
+		if p.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Digest is the sha256 of dir's manifest file. Recorded outside dir, it
+// shows whether the manifest itself was rewritten.
+func Digest(dir string) (string, error) {
+	return hashFile(filepath.Join(dir, FileName))
+}
+
+// Verify compares dir with its manifest and lists files that were changed,
+// removed or added since the manifest was written.
+func (m *Manifest) Verify(dir string) ([]Problem, error) {
+	onDisk, err := Scan(dir)
+	if err != nil {
+		return nil, err
+	}
+	actual := make(map[string]File, len(onDisk))
+	for _, f := range onDisk {
+		actual[f.Path] = f
+	}
+
+	var problems []Problem
+	for _, want := range m.Files {
+		got, ok := actual[want.Path]
+		delete(actual, want.Path)
+		switch {
+		case !ok:
+			problems = append(problems, Problem{want.Path, "missing"})
+		case want.Link != "" || got.Link != "":
+			if got.Link != want.Link {
+				problems = append(problems, Problem{want.Path, fmt.Sprintf("link changed: %q, expected %q", got.Link, want.Link)})
+			}
+		case got.SHA256 != want.SHA256:
+			problems = append(problems, Problem{want.Path, fmt.Sprintf("content changed: sha256 %s, expected %s", got.SHA256, want.SHA256)})
+		case got.Mode != want.Mode && runtime.GOOS != "windows":
+			// Windows has no permission bits to compare.
+			problems = append(problems, Problem{want.Path, fmt.Sprintf("mode changed: %s, expected %s", got.Mode, want.Mode)})
+		}
+	}
+	for _, f := range onDisk {
+		if _, added := actual[f.Path]; added {
+			problems = append(problems, Problem{f.Path, "not in the manifest"})
+		}
+	}
+
+	// The downloaded archive or binary is usually kept; check it against
+	// the digest taken at download time, not only the file list.
+	if m.Asset != nil && m.Asset.SHA256 != "" {
+		path := filepath.Join(dir, filepath.FromSlash(m.Asset.Name))
+		if got, err := hashFile(path); err == nil && got != m.Asset.SHA256 && !reported(problems, m.Asset.Name) {
+			problems = append(problems, Problem{m.Asset.Name, fmt.Sprintf("download changed: sha256 %s, recorded at download %s", got, m.Asset.SHA256)})
+		}
+	}
+	return problems, nil
+}
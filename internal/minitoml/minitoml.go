@@ -0,0 +1,202 @@
+// Package minitoml parses the small TOML subset used by tool manifests:
+// [tables] (bare or quoted names), key = value pairs with string, boolean,
+// integer and string-array values, and # comments. It is deliberately not a
+// complete TOML implementation.
+package minitoml
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Table maps keys to string, bool, int64 or []string values.
+type Table map[string]interface{}
+
+// Document maps table names to tables. Keys before the first table header
+// live under the empty name.
+type Document map[string]Table
+
+// Parse parses data into a Document.
+func Parse(data []byte) (Document, error) {
+	doc := Document{"": Table{}}
+	current := doc[""]
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo, start := 0, 0 // start is the line the current entry begins on
+	var pending string    // accumulates multi-line arrays
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if pending != "" {
+			pending += " " + line
+			if indexUnquoted(line, ']') < 0 {
+				continue
+			}
+			line, pending = pending, ""
+		} else {
+			start = lineNo
+		}
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: unsupported table header %q", start, line)
+			}
+			name, err := parseKey(strings.TrimSpace(line[1 : len(line)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", start, err)
+			}
+			if _, ok := doc[name]; !ok {
+				doc[name] = Table{}
+			}
+			current = doc[name]
+			continue
+		}
+
+		eq := indexUnquoted(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", start)
+		}
+		rawKey, rawValue := line[:eq], strings.TrimSpace(line[eq+1:])
+		if strings.HasPrefix(rawValue, "[") && indexUnquoted(rawValue, ']') < 0 {
+			pending = line
+			continue
+		}
+		key, err := parseKey(strings.TrimSpace(rawKey))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+		value, err := parseValue(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+		current[key] = value
+	}
+	if pending != "" {
+		return nil, fmt.Errorf("line %d: unterminated array", start)
+	}
+	return doc, scanner.Err()
+}
+
+// String returns the string value of key, or "".
+func (t Table) String(key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+// Strings returns the array value of key. A plain string is returned as a
+// one-element slice.
+func (t Table) Strings(key string) []string {
+	switch v := t[key].(type) {
+	case []string:
+		return v
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+func parseKey(s string) (string, error) {
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, `'`) {
+		return parseString(s)
+	}
+	if s == "" {
+		return "", fmt.Errorf("empty key")
+	}
+	return s, nil
+}
+
+func parseValue(s string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, `'`):
+		return parseString(s)
+	case s == "true" || s == "false":
+		return s == "true", nil
+	case strings.HasPrefix(s, "["):
+		return parseArray(s)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	return nil, fmt.Errorf("unsupported value %q", s)
+}
+
+func parseString(s string) (string, error) {
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+	if s[0] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+func parseArray(s string) ([]string, error) {
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("unterminated array %s", s)
+	}
+	var out []string
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	for body != "" {
+		if body[0] != '"' && body[0] != '\'' {
+			return nil, fmt.Errorf("only string arrays are supported: %s", s)
+		}
+		end := closingQuote(body)
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated string in array %s", s)
+		}
+		item, err := parseString(body[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+		body = strings.TrimSpace(body[end+1:])
+		body = strings.TrimSpace(strings.TrimPrefix(body, ","))
+	}
+	return out, nil
+}
+
+// closingQuote returns the index of the quote ending the string that starts at s[0].
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// stripComment removes a trailing # comment that is not inside a string.
+func stripComment(line string) string {
+	if i := indexUnquoted(line, '#'); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// indexUnquoted returns the index of the first c in line that is not inside
+// a string, or -1.
+func indexUnquoted(line string, c byte) int {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch {
+		case quote != 0 && line[i] == '\\' && quote == '"':
+			i++
+		case quote != 0 && line[i] == quote:
+			quote = 0
+		case quote == 0 && (line[i] == '"' || line[i] == '\''):
+			quote = line[i]
+		case quote == 0 && line[i] == c:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,93 @@
+package minitoml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Document
+	}{
+		{
+			"quoted keys",
+			"[tools]\n\"BurntSushi/ripgrep\" = \"14.1.0\"\n'a=b' = 'x'\n\"#hash\" = \"y\"\n",
+			Document{"": {}, "tools": {"BurntSushi/ripgrep": "14.1.0", "a=b": "x", "#hash": "y"}},
+		},
+		{
+			"quoted table name",
+			"[\"my tools\"]\nk = true\n",
+			Document{"": {}, "my tools": {"k": true}},
+		},
+		{
+			"scalars",
+			"s = \"a\\tb\"\nlit = 'C:\\path'\nn = 42\nb = false\n",
+			Document{"": {"s": "a\tb", "lit": `C:\path`, "n": int64(42), "b": false}},
+		},
+		{
+			"comments inside strings",
+			"url = \"https://example.com/#frag\" # trailing\nq = 'it # is'\n# whole line\n",
+			Document{"": {"url": "https://example.com/#frag", "q": "it # is"}},
+		},
+		{
+			"arrays",
+			"a = [\"x\", 'y',]\nempty = []\nsep = [\"]\", \"a,b\"]\n",
+			Document{"": {"a": []string{"x", "y"}, "empty": []string(nil), "sep": []string{"]", "a,b"}}},
+		},
+		{
+			"multi-line arrays",
+			"a = [\n  \"x\", # first\n\n  \"]\",\n  \"z\",\n]\nafter = 1\n",
+			Document{"": {"a": []string{"x", "]", "z"}, "after": int64(1)}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse([]byte(tt.input))
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Parse = %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"a = 1\nno equals\n", "line 2: expected key = value"},
+		{"[[tools]]\n", `line 1: unsupported table header "[[tools]]"`},
+		{"a = 1\n\nb = \"open\n", "line 3: unterminated string \"open"},
+		{"a = 1.5\n", `line 1: unsupported value "1.5"`},
+		{"= 1\n", "line 1: empty key"},
+		{"a = [1, 2]\n", "line 1: only string arrays are supported: [1, 2]"},
+		{"x = 1\na = [\n  \"b\",\n  3,\n]\n", "line 2: only string arrays are supported: [ \"b\", 3, ]"},
+		{"a = [\n  \"b\",\n", "line 1: unterminated array"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.input))
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestTableStrings(t *testing.T) {
+	table := Table{"one": "a", "many": []string{"a", "b"}, "n": int64(1)}
+	if got := table.Strings("one"); !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("Strings(one) = %v", got)
+	}
+	if got := table.Strings("many"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Strings(many) = %v", got)
+	}
+	if got := table.Strings("n"); got != nil {
+		t.Errorf("Strings(n) = %v, want nil", got)
+	}
+	if got := table.String("many"); got != "" {
+		t.Errorf("String(many) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,80 @@
+// Package paths resolves where track keeps its config, installed tools and
+// caches.
+//
+// On Linux, macOS and other Unix systems the XDG base directories are used:
+//
+//	config  $XDG_CONFIG_HOME/track  (~/.config/track)
+//	data    $XDG_DATA_HOME/track    (~/.local/share/track)
+//	cache   $XDG_CACHE_HOME/track   (~/.cache/track)
+//
+// On Windows config and data live in %LOCALAPPDATA%\track and the cache in
+// %LOCALAPPDATA%\track\cache. Setting TRACK_HOME keeps everything in that one
+// directory instead, which is useful for portable installs and tests.
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// HomeEnv overrides all other locations when set.
+const HomeEnv = "TRACK_HOME"
+
+// ConfigDir holds config.json and track.lock.
+func ConfigDir() (string, error) {
+	return resolve("XDG_CONFIG_HOME", ".config", "")
+}
+
+// DataDir is the default data_dir for installed tools.
+func DataDir() (string, error) {
+	return resolve("XDG_DATA_HOME", filepath.Join(".local", "share"), "")
+}
+
+// CacheDir holds files that can be deleted at any time, like 'track run'
+// downloads.
+func CacheDir() (string, error) {
+	return resolve("XDG_CACHE_HOME", ".cache", "cache")
+}
+
+// LegacyConfigDir is where track kept config.json and, by default, its data
+// before it followed the XDG layout. It returns "" where nothing moved.
+func LegacyConfigDir() string {
+	if runtime.GOOS == "windows" || os.Getenv(HomeEnv) != "" {
+		return ""
+	}
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cache, "track")
+}
+
+// resolve returns the track directory below xdgEnv (or ~/homeRel when it is
+// unset). sub is appended below %LOCALAPPDATA%\track and TRACK_HOME.
+func resolve(xdgEnv, homeRel, sub string) (string, error) {
+	if home := os.Getenv(HomeEnv); home != "" {
+		abs, err := filepath.Abs(home)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", HomeEnv, err)
+		}
+		return filepath.Join(abs, sub), nil
+	}
+	if runtime.GOOS == "windows" {
+		localAppData := os.Getenv("LOCALAPPDATA")
+		if localAppData == "" {
+			return "", fmt.Errorf("LOCALAPPDATA not set")
+		}
+		return filepath.Join(localAppData, "track", sub), nil
+	}
+	// The XDG spec says relative values are invalid and must be ignored.
+	if base := os.Getenv(xdgEnv); base != "" && filepath.IsAbs(base) {
+		return filepath.Join(base, "track"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	return filepath.Join(home, homeRel, "track"), nil
+}
@@ -0,0 +1,196 @@
+package portable
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/minitoml"
+)
+
+// repoPattern matches owner/repo.
+var repoPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// Parse reads an import file. The format is detected from the file name
+// (.eget.toml, aqua.yaml) or the content: a JSON object is track's export
+// format, anything else a list of owner/repo lines.
+func Parse(name string, data []byte) (*Import, error) {
+	base := strings.ToLower(filepath.Base(name))
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case strings.HasSuffix(base, ".toml"):
+		return parseEget(data)
+	case strings.HasSuffix(base, ".yaml") || strings.HasSuffix(base, ".yml"):
+		return parseAqua(data)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return parseExport(data)
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return parseEget(data)
+	case regexp.MustCompile(`(?m)^\s*packages:`).Match(data):
+		return parseAqua(data)
+	}
+	return parseList(data)
+}
+
+// normalizeRepo accepts owner/repo, owner/repo@tag and GitHub URLs.
+func normalizeRepo(s string) (repo, tag string, ok bool) {
+	s = strings.TrimSpace(s)
+	for _, prefix := range []string{"https://github.com/", "http://github.com/", "github.com/"} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	repo, tag, _ = strings.Cut(s, "@")
+	return repo, tag, repoPattern.MatchString(repo)
+}
+
+// parseList reads one owner/repo (optionally @tag) per line; # starts a
+// comment.
+func parseList(data []byte) (*Import, error) {
+	imp := &Import{Format: "list"}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		repo, tag, ok := normalizeRepo(fields[0])
+		if !ok || len(fields) > 1 {
+			return nil, fmt.Errorf("line %d: expected owner/repo, got %q", lineNo, strings.TrimSpace(line))
+		}
+		imp.Entries = append(imp.Entries, Entry{Path: repo, Repo: &config.Repo{}, Tag: tag})
+	}
+	return imp, scanner.Err()
+}
+
+// parseEget reads an eget config: one [owner/repo] table per tool.
+// asset_filters become asset_filter and asset_exclude; "^name" filters
+// exclude assets.
+func parseEget(data []byte) (*Import, error) {
+	doc, err := minitoml.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	imp := &Import{Format: "eget"}
+	names := make([]string, 0, len(doc))
+	for name := range doc {
+		if name != "" && name != "global" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		repo, _, ok := normalizeRepo(name)
+		if !ok {
+			return nil, fmt.Errorf("[%s]: expected an owner/repo table", name)
+		}
+		t := doc[name]
+		entry := Entry{Path: repo, Repo: &config.Repo{}}
+
+		var include, exclude []string
+		for _, f := range t.Strings("asset_filters") {
+			if strings.HasPrefix(f, "^") {
+				exclude = append(exclude, regexp.QuoteMeta(strings.ToLower(f[1:])))
+			} else {
+				include = append(include, regexp.QuoteMeta(strings.ToLower(f)))
+			}
+		}
+		if len(include) > 0 {
+			// eget requires every filter to match; without lookaheads the
+			// closest regex expects them in the order given.
+			entry.Repo.AssetFilter = strings.Join(include, ".*")
+			if len(include) > 1 {
+				entry.Notes = append(entry.Notes, fmt.Sprintf("asset_filters %v must now match in this order: %s", t.Strings("asset_filters"), entry.Repo.AssetFilter))
+			}
+		}
+		if len(exclude) > 0 {
+			entry.Repo.AssetExclude = strings.Join(exclude, "|")
+		}
+		if tag := t.String("tag"); tag != "" {
+			entry.Tag = tag
+		}
+		if pre, ok := t["pre_release"].(bool); ok {
+			entry.Repo.IncludePrerelease = pre
+		}
+		for key := range t {
+			switch key {
+			case "asset_filters", "tag", "pre_release":
+			default:
+				entry.Notes = append(entry.Notes, fmt.Sprintf("eget setting %q is not supported and was ignored", key))
+			}
+		}
+		sort.Strings(entry.Notes)
+		imp.Entries = append(imp.Entries, entry)
+	}
+	return imp, nil
+}
+
+// parseAqua reads the packages list of an aqua.yaml:
+//
+//	packages:
+//	- name: cli/cli@v2.2.0
+//	- name: junegunn/fzf
+//	  version: 0.44.1
+//
+// Only this shape is understood; registries and other keys are ignored.
+func parseAqua(data []byte) (*Import, error) {
+	imp := &Import{Format: "aqua"}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	inPackages := false
+	lineNo := 0
+	// current is the index of the entry the item being read produced, or
+	// -1 if it has produced none yet (or its name was skipped); version is
+	// the item's version when it comes before its name.
+	current, version := -1, ""
+	for scanner.Scan() {
+		lineNo++
+		raw, _, _ := strings.Cut(scanner.Text(), " #")
+		if strings.HasPrefix(strings.TrimSpace(raw), "#") || strings.TrimSpace(raw) == "" {
+			continue
+		}
+		if !strings.HasPrefix(raw, " ") && !strings.HasPrefix(raw, "-") {
+			inPackages = strings.TrimSpace(raw) == "packages:"
+			continue
+		}
+		if !inPackages {
+			continue
+		}
+		line := strings.TrimSpace(raw)
+		if strings.HasPrefix(line, "-") {
+			current, version = -1, ""
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "-"))
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "name":
+			repo, tag, ok := normalizeRepo(value)
+			if !ok {
+				imp.Skipped = append(imp.Skipped, fmt.Sprintf("%s (line %d): not an owner/repo package", value, lineNo))
+				continue
+			}
+			if tag == "" {
+				tag = version
+			}
+			imp.Entries = append(imp.Entries, Entry{Path: repo, Repo: &config.Repo{}, Tag: tag})
+			current = len(imp.Entries) - 1
+		case "version":
+			if current >= 0 {
+				imp.Entries[current].Tag = value
+			} else {
+				version = value
+			}
+		}
+	}
+	return imp, scanner.Err()
+}
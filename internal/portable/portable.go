@@ -0,0 +1,188 @@
+// Package portable converts tracked repositories to and from files that can
+// be shared between machines: track's own export format, plain owner/repo
+// lists, eget's .eget.toml and aqua's aqua.yaml.
+package portable
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/track/internal/config"
+)
+
+// FormatVersion is the version of the export format written by Export.
+const FormatVersion = 1
+
+// machineKeys are global settings that describe one machine and are never
+// exported or imported.
+var machineKeys = map[string]bool{
+	"data_dir": true,
+}
+
+// File is the export format: repo settings and global settings, without
+// installed versions or machine-specific paths.
+type File struct {
+	Version int                        `json:"track_export"`
+	Global  map[string]json.RawMessage `json:"global,omitempty"`
+	Repos   map[string]json.RawMessage `json:"repos"`
+}
+
+// Entry is one repository to import.
+type Entry struct {
+	Path  string
+	Repo  *config.Repo
+	Tag   string   // version to install instead of the latest, if the source pins one
+	Notes []string // settings of the source format that could not be carried over
+}
+
+// Import is the parsed content of an import file.
+type Import struct {
+	Format  string // "track", "list", "eget" or "aqua"
+	Global  map[string]string
+	Entries []Entry
+	Skipped []string // entries of the source that cannot be imported, with the reason
+}
+
+// Export writes the given repos and the global settings of cfg.
+func Export(cfg *config.Config, repos []string) ([]byte, error) {
+	f := File{Version: FormatVersion, Repos: map[string]json.RawMessage{}}
+	global, err := toObject(&cfg.Global)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range global {
+		if machineKeys[key] || isZero(value) {
+			delete(global, key)
+		}
+	}
+	f.Global = global
+
+	for _, repoPath := range repos {
+		repo, ok := cfg.Repos[repoPath]
+		if !ok {
+			return nil, fmt.Errorf("repository '%s' is not tracked", repoPath)
+		}
+		if f.Repos[repoPath], err = RepoSettings(repo); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RepoSettings is the portable form of a repo, as Export writes it: every
+// setting, without the state track maintains.
+func RepoSettings(repo *config.Repo) (json.RawMessage, error) {
+	obj, err := toObject(repo)
+	if err != nil {
+		return nil, err
+	}
+	dropState(obj)
+	if isZero(obj["include_prerelease"]) {
+		delete(obj, "include_prerelease")
+	}
+	return marshal(obj)
+}
+
+// ParseRepoSettings reads settings written by RepoSettings, ignoring any
+// state keys.
+func ParseRepoSettings(data json.RawMessage) (*config.Repo, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	dropState(obj)
+	clean, _ := json.Marshal(obj)
+	repo := &config.Repo{}
+	if err := json.Unmarshal(clean, repo); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// marshal is json.Marshal without escaping <, > and &, which are common
+// in regexes and URL templates.
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func toObject(v interface{}) (map[string]json.RawMessage, error) {
+	data, err := marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	return obj, json.Unmarshal(data, &obj)
+}
+
+// dropState removes the repo keys track maintains itself.
+func dropState(obj map[string]json.RawMessage) {
+	for _, field := range config.RepoFields() {
+		if field.State {
+			delete(obj, strings.Split(field.Key, ".")[0])
+		}
+	}
+}
+
+func isZero(value json.RawMessage) bool {
+	switch string(value) {
+	case "", "null", "false", "0", `""`, "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// parseExport reads the export format. A full config.json is accepted too;
+// its state and machine-specific keys are dropped.
+func parseExport(data []byte) (*Import, error) {
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Version > FormatVersion {
+		return nil, fmt.Errorf("export format %d is newer than this track supports (%d); upgrade track", f.Version, FormatVersion)
+	}
+	imp := &Import{Format: "track", Global: map[string]string{}}
+	for key, raw := range f.Global {
+		if machineKeys[key] {
+			continue
+		}
+		// Strings are passed unquoted; lists stay JSON arrays, which
+		// config.Field.Set accepts, so items may contain commas.
+		var s string
+		if json.Unmarshal(raw, &s) != nil {
+			s = string(raw)
+		}
+		imp.Global[key] = s
+	}
+
+	keys := make([]string, 0, len(f.Repos))
+	for k := range f.Repos {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		repo, err := ParseRepoSettings(f.Repos[k])
+		if err != nil {
+			return nil, fmt.Errorf("repos[%q]: %w", k, err)
+		}
+		imp.Entries = append(imp.Entries, Entry{Path: k, Repo: repo})
+	}
+	return imp, nil
+}
@@ -0,0 +1,82 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/user/track/internal/minitoml"
+)
+
+// Manifest file names, checked in this order in each directory.
+const (
+	JSONFile = ".track.json"
+	TOMLFile = ".track.toml"
+)
+
+// Manifest declares the tools and exact versions a project needs.
+//
+// .track.json:
+//
+//	{"tools": {"BurntSushi/ripgrep": "14.1.0"}}
+//
+// .track.toml:
+//
+//	[tools]
+//	"BurntSushi/ripgrep" = "14.1.0"
+type Manifest struct {
+	Path  string            `json:"-"`
+	Tools map[string]string `json:"tools"` // owner/repo -> tag
+}
+
+// Find returns the manifest in dir or its nearest parent, or nil if there is none.
+func Find(dir string) (*Manifest, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		for _, name := range []string{JSONFile, TOMLFile} {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return Load(path)
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Load reads a .track.json or .track.toml manifest.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	m := &Manifest{Path: path, Tools: make(map[string]string)}
+	if filepath.Ext(path) == ".toml" {
+		doc, err := minitoml.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		for repo := range doc["tools"] {
+			tag := doc["tools"].String(repo)
+			if tag == "" {
+				return nil, fmt.Errorf("%s: version of '%s' must be a string", path, repo)
+			}
+			m.Tools[repo] = tag
+		}
+		return m, nil
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if m.Tools == nil {
+		m.Tools = make(map[string]string)
+	}
+	return m, nil
+}
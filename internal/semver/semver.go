@@ -0,0 +1,144 @@
+package semver
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Missing minor/patch parts are zero.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string
+	Original            string
+}
+
+var versionRe = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:[-.]?([0-9A-Za-z.\-]+?))?(?:\+[0-9A-Za-z.\-]+)?$`)
+
+// findRe locates a version-looking token inside arbitrary text.
+var findRe = regexp.MustCompile(`v?\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.\-]+)?`)
+
+// Parse parses tags like "v1.2.3", "1.2", "14.1.0-rc.1". The second return
+// value is false if s does not look like a version.
+func Parse(s string) (Version, bool) {
+	tag := strings.TrimSpace(s)
+	// Tags like "jq-1.7.1" or "tool/v1.2.3" are common in monorepos.
+	if i := strings.LastIndex(tag, "/"); i >= 0 {
+		tag = tag[i+1:]
+	}
+	if i := strings.IndexAny(tag, "0123456789"); i > 0 {
+		tag = tag[i:]
+	}
+	m := versionRe.FindStringSubmatch(tag)
+	if m == nil {
+		return Version{}, false
+	}
+	v := Version{Original: s, Pre: m[4]}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	return v, true
+}
+
+// Find returns the first version-looking token in text, or "".
+func Find(text string) string {
+	return findRe.FindString(text)
+}
+
+// IsPrerelease reports whether the version has a pre-release suffix.
+func (v Version) IsPrerelease() bool {
+	return v.Pre != ""
+}
+
+// Compare returns -1, 0 or 1. Pre-releases sort before their release.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	}
+	return comparePre(v.Pre, o.Pre)
+}
+
+// comparePre compares pre-release suffixes by their dot-separated
+// identifiers: numeric ones as numbers, so rc.10 sorts after rc.9, and
+// numeric before alphanumeric. A shorter suffix sorts first when all its
+// identifiers are equal.
+func comparePre(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// Compare parses and compares two version strings. Unparseable versions
+// sort before parseable ones and are otherwise compared as strings.
+func Compare(a, b string) int {
+	va, okA := Parse(a)
+	vb, okB := Parse(b)
+	switch {
+	case okA && okB:
+		return va.Compare(vb)
+	case okA:
+		return 1
+	case okB:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// Equal reports whether two tags name the same version, ignoring a
+// leading "v" and any tag prefix.
+func Equal(a, b string) bool {
+	va, okA := Parse(a)
+	vb, okB := Parse(b)
+	if !okA || !okB {
+		return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+	}
+	return va.Compare(vb) == 0
+}
+
+// SortDesc sorts version strings newest first.
+func SortDesc(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return Compare(versions[i], versions[j]) > 0
+	})
+}
@@ -0,0 +1,42 @@
+package semver
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-rc.10", "1.0.0-rc.9", 1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha", 1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-beta.11", "1.0.0-beta.2", 1},
+		{"jq-1.7.1", "1.7", 1},
+		{"nightly", "1.0.0", -1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestSortDesc(t *testing.T) {
+	versions := []string{"v1.0.0-rc.9", "v1.0.0", "v1.0.0-rc.10", "v0.9.0"}
+	SortDesc(versions)
+	want := []string{"v1.0.0", "v1.0.0-rc.10", "v1.0.0-rc.9", "v0.9.0"}
+	for i := range want {
+		if versions[i] != want[i] {
+			t.Fatalf("SortDesc = %v, want %v", versions, want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package source
+
+import (
+	"fmt"
+
+	"github.com/user/track/internal/semver"
+)
+
+// LatestTag picks the highest semver tag. Tags that are not versions are
+// ignored, as are tags that cannot name a directory (release/v1.0) and
+// pre-releases unless includePrerelease is set.
+func LatestTag(tags []string, includePrerelease bool) (string, error) {
+	var versions []string
+	for _, tag := range tags {
+		v, ok := semver.Parse(tag)
+		if !ok || (v.IsPrerelease() && !includePrerelease) || CheckTag(tag) != nil {
+			continue
+		}
+		versions = append(versions, tag)
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("no version tags found")
+	}
+	semver.SortDesc(versions)
+	return versions[0], nil
+}
@@ -0,0 +1,16 @@
+package source
+
+import "testing"
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"v1.2.0", "release/v1.3.0", "v1.4.0-rc.1", "nightly", "v1.10.0"}
+	if got, err := LatestTag(tags, false); err != nil || got != "v1.10.0" {
+		t.Errorf("LatestTag = %q, %v, want v1.10.0", got, err)
+	}
+	if got, err := LatestTag([]string{"v1.0.0", "v1.1.0-rc.1"}, true); err != nil || got != "v1.1.0-rc.1" {
+		t.Errorf("LatestTag with prereleases = %q, %v, want v1.1.0-rc.1", got, err)
+	}
+	if got, err := LatestTag([]string{"release/v2.0.0", "nightly"}, false); err == nil {
+		t.Errorf("LatestTag = %q, want an error", got)
+	}
+}
@@ -0,0 +1,276 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/user/track/internal/config"
+	"github.com/user/track/internal/semver"
+)
+
+// defaultVersionPattern is used when a version check has no regex.
+const defaultVersionPattern = `v?\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.\-]+)?`
+
+// httpClient is used for version checks. Unlike http.DefaultClient it gives
+// up on a server that stops responding.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// URLSource tracks a tool published at a plain vendor download URL.
+type URLSource struct {
+	Template          string
+	Check             *config.VersionCheck
+	IncludePrerelease bool
+
+	// Client is used for all requests; tests can point it at an httptest server.
+	Client *http.Client
+}
+
+// TemplateData is the data available to URL templates.
+type TemplateData struct {
+	Tag     string // tag as discovered, e.g. "v1.29.0"
+	Version string // tag without a leading "v"
+	OS      string // runtime.GOOS
+	Arch    string // runtime.GOARCH
+	Ext     string // ".exe" on Windows, empty elsewhere
+}
+
+func NewURL(repoCfg *config.Repo) *URLSource {
+	return &URLSource{
+		Template:          repoCfg.URLTemplate,
+		Check:             repoCfg.VersionCheck,
+		IncludePrerelease: repoCfg.IncludePrerelease,
+		Client:            httpClient,
+	}
+}
+
+// LatestRelease discovers the newest version and returns a release with a
+// single asset pointing at the templated download URL.
+func (s *URLSource) LatestRelease(ctx context.Context) (*github.RepositoryRelease, error) {
+	tag, err := s.LatestVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.Release(tag)
+}
+
+// LatestVersion runs the configured version check.
+func (s *URLSource) LatestVersion(ctx context.Context) (string, error) {
+	tag, err := s.latestVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := CheckTag(tag); err != nil {
+		return "", fmt.Errorf("version_check: %w", err)
+	}
+	return tag, nil
+}
+
+func (s *URLSource) latestVersion(ctx context.Context) (string, error) {
+	if s.Check == nil || s.Check.URL == "" {
+		return "", fmt.Errorf("url source has no version_check configured")
+	}
+	pattern := s.Check.Regex
+	if pattern == "" {
+		pattern = defaultVersionPattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid version_check regex: %w", err)
+	}
+
+	resp, err := s.get(ctx, s.Check.URL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch s.Check.Strategy {
+	case "json":
+		var doc interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+			return "", fmt.Errorf("could not decode version index: %w", err)
+		}
+		value, err := lookupJSONPath(doc, s.Check.JSONPath)
+		if err != nil {
+			return "", err
+		}
+		if s.Check.Regex == "" {
+			return value, nil
+		}
+		return firstMatch(re, value)
+	case "redirect":
+		// The client follows redirects; the version is read from the final URL.
+		return firstMatch(re, resp.Request.URL.String())
+	case "regex":
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		return s.highestMatch(re, string(body))
+	}
+	return "", fmt.Errorf("unknown version_check strategy '%s' (use json, redirect or regex)", s.Check.Strategy)
+}
+
+// Release builds a release for tag whose only asset is the rendered download URL.
+func (s *URLSource) Release(tag string) (*github.RepositoryRelease, error) {
+	if err := CheckTag(tag); err != nil {
+		return nil, err
+	}
+	downloadURL, err := Render(s.Template, tag)
+	if err != nil {
+		return nil, err
+	}
+	return NewRelease(tag, downloadURL)
+}
+
+// CheckTag rejects versions that cannot name a directory: installs go to
+// <name>/general/<tag>.
+func CheckTag(tag string) error {
+	if tag == "" || tag == "." || tag == ".." || strings.ContainsAny(tag, `/\`) {
+		return fmt.Errorf("version '%s' cannot be used as a directory name", tag)
+	}
+	return nil
+}
+
+// Render expands a URL template for tag on the current platform.
+func Render(tmpl, tag string) (string, error) {
+	if tmpl == "" {
+		return "", fmt.Errorf("no url_template configured")
+	}
+	t, err := template.New("url").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid url_template: %w", err)
+	}
+	data := TemplateData{
+		Tag:     tag,
+		Version: strings.TrimPrefix(tag, "v"),
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+	}
+	if runtime.GOOS == "windows" {
+		data.Ext = ".exe"
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("could not render url_template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// NewRelease wraps a single download URL in a release so it can flow through
+// the same install pipeline as GitHub releases.
+func NewRelease(tag, downloadURL string) (*github.RepositoryRelease, error) {
+	u, err := url.Parse(downloadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid download URL '%s': %w", downloadURL, err)
+	}
+	name := path.Base(u.Path)
+	if name == "" || name == "/" || name == "." {
+		return nil, fmt.Errorf("download URL '%s' has no file name", downloadURL)
+	}
+	return &github.RepositoryRelease{
+		TagName: github.String(tag),
+		Name:    github.String(tag),
+		Assets: []*github.ReleaseAsset{{
+			Name:               github.String(name),
+			BrowserDownloadURL: github.String(downloadURL),
+		}},
+	}, nil
+}
+
+func (s *URLSource) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := s.Client
+	if client == nil {
+		client = httpClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %w", rawURL, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("could not fetch %s: bad status: %s", rawURL, resp.Status)
+	}
+	return resp, nil
+}
+
+// highestMatch returns the newest version among all matches in text.
+func (s *URLSource) highestMatch(re *regexp.Regexp, text string) (string, error) {
+	var best string
+	for _, m := range re.FindAllStringSubmatch(text, -1) {
+		candidate := m[0]
+		if len(m) > 1 {
+			candidate = m[1]
+		}
+		if v, ok := semver.Parse(candidate); ok && v.IsPrerelease() && !s.IncludePrerelease {
+			continue
+		}
+		if best == "" || semver.Compare(candidate, best) > 0 {
+			best = candidate
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no version matching '%s' found", re.String())
+	}
+	return best, nil
+}
+
+func firstMatch(re *regexp.Regexp, text string) (string, error) {
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return "", fmt.Errorf("no version matching '%s' found in '%s'", re.String(), text)
+	}
+	if len(m) > 1 {
+		return m[1], nil
+	}
+	return m[0], nil
+}
+
+// lookupJSONPath walks a dot-separated path such as "versions.0.tag".
+func lookupJSONPath(doc interface{}, jsonPath string) (string, error) {
+	cur := doc
+	if jsonPath != "" {
+		for _, part := range strings.Split(jsonPath, ".") {
+			switch node := cur.(type) {
+			case map[string]interface{}:
+				next, ok := node[part]
+				if !ok {
+					return "", fmt.Errorf("json_path '%s': key '%s' not found", jsonPath, part)
+				}
+				cur = next
+			case []interface{}:
+				i, err := strconv.Atoi(part)
+				if err != nil || i < 0 || i >= len(node) {
+					return "", fmt.Errorf("json_path '%s': invalid index '%s'", jsonPath, part)
+				}
+				cur = node[i]
+			default:
+				return "", fmt.Errorf("json_path '%s': cannot descend into '%s'", jsonPath, part)
+			}
+		}
+	}
+	switch v := cur.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("json_path '%s' does not point to a version string", jsonPath)
+}
@@ -0,0 +1,104 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/track/internal/config"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"channels": {"stable": {"version": "v1.29.0"}}, "versions": [{"tag": "tool-2.0.1"}]}`))
+	})
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/download/v3.4.5/tool.tar.gz", http.StatusFound)
+	})
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("archive"))
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<a>tool-1.9.0.tar.gz</a> <a>tool-1.10.0.tar.gz</a> <a>tool-1.11.0-rc.1.tar.gz</a> <a>tool-1.2.0.tar.gz</a>`))
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLatestVersion(t *testing.T) {
+	srv := newTestServer(t)
+	tests := []struct {
+		name       string
+		check      config.VersionCheck
+		prerelease bool
+		want       string
+	}{
+		{"json", config.VersionCheck{Strategy: "json", URL: "/index.json", JSONPath: "channels.stable.version"}, false, "v1.29.0"},
+		{"json array with regex", config.VersionCheck{Strategy: "json", URL: "/index.json", JSONPath: "versions.0.tag", Regex: `\d+\.\d+\.\d+`}, false, "2.0.1"},
+		{"redirect", config.VersionCheck{Strategy: "redirect", URL: "/latest", Regex: `/download/(v[\d.]+)/`}, false, "v3.4.5"},
+		{"regex picks highest", config.VersionCheck{Strategy: "regex", URL: "/page.html", Regex: `tool-([\d.]+(?:-rc\.\d+)?)\.tar\.gz`}, false, "1.10.0"},
+		{"regex with prereleases", config.VersionCheck{Strategy: "regex", URL: "/page.html", Regex: `tool-([\d.]+(?:-rc\.\d+)?)\.tar\.gz`}, true, "1.11.0-rc.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := tt.check
+			check.URL = srv.URL + check.URL
+			s := &URLSource{Template: srv.URL + "/download/{{.Tag}}/tool.tar.gz", Check: &check, IncludePrerelease: tt.prerelease, Client: srv.Client()}
+			got, err := s.LatestVersion(context.Background())
+			if err != nil {
+				t.Fatalf("LatestVersion: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("LatestVersion = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestVersionErrors(t *testing.T) {
+	srv := newTestServer(t)
+	tests := []struct {
+		name  string
+		check config.VersionCheck
+	}{
+		{"bad status", config.VersionCheck{Strategy: "regex", URL: "/missing"}},
+		{"missing json key", config.VersionCheck{Strategy: "json", URL: "/index.json", JSONPath: "channels.beta.version"}},
+		{"no match", config.VersionCheck{Strategy: "regex", URL: "/page.html", Regex: `nomatch-(\d+)`}},
+		{"unknown strategy", config.VersionCheck{Strategy: "rss", URL: "/page.html"}},
+		{"tag with a slash", config.VersionCheck{Strategy: "redirect", URL: "/latest", Regex: `(download/v[\d.]+)/`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := tt.check
+			check.URL = srv.URL + check.URL
+			s := &URLSource{Check: &check, Client: srv.Client()}
+			if got, err := s.LatestVersion(context.Background()); err == nil {
+				t.Errorf("LatestVersion = %q, want an error", got)
+			}
+		})
+	}
+}
+
+func TestLatestRelease(t *testing.T) {
+	srv := newTestServer(t)
+	check := config.VersionCheck{Strategy: "json", URL: srv.URL + "/index.json", JSONPath: "channels.stable.version"}
+	s := &URLSource{Template: srv.URL + "/download/{{.Tag}}/tool-{{.Version}}.tar.gz", Check: &check, Client: srv.Client()}
+	release, err := s.LatestRelease(context.Background())
+	if err != nil {
+		t.Fatalf("LatestRelease: %v", err)
+	}
+	if release.GetTagName() != "v1.29.0" || len(release.Assets) != 1 {
+		t.Fatalf("LatestRelease = %s with %d assets", release.GetTagName(), len(release.Assets))
+	}
+	asset := release.Assets[0]
+	if asset.GetName() != "tool-1.29.0.tar.gz" || asset.GetBrowserDownloadURL() != srv.URL+"/download/v1.29.0/tool-1.29.0.tar.gz" {
+		t.Errorf("asset = %s at %s", asset.GetName(), asset.GetBrowserDownloadURL())
+	}
+}